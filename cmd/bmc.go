@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/fanout"
+	"bootstrap/internal/redfish"
+	"bootstrap/internal/xname"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bmcFile     string
+	bmcHostsCSV string
+	bmcInsecure bool
+	bmcTimeout  time.Duration
+	bmcDryRun   bool
+
+	bmcBatchSize     int
+	bmcMaxPerCabinet int
+	bmcMaxPerChassis int
+
+	rstType        string
+	rstWait        bool
+	rstWaitTimeout time.Duration
+	rstInterval    time.Duration
+	rstSettle      time.Duration
+	rstStagger     bool
+)
+
+var bmcCmd = &cobra.Command{
+	Use:   "bmc",
+	Short: "Manage BMCs via Redfish",
+}
+
+// bmcLimits returns the concurrency limits for bmc fan-out: --batch-size overall (0 = no
+// limit), --max-per-cabinet and --max-per-chassis.
+func bmcLimits() fanout.Limits {
+	return fanout.Limits{Global: bmcBatchSize, PerCabinet: bmcMaxPerCabinet, PerChassis: bmcMaxPerChassis}
+}
+
+// staggerRounds splits hosts into rounds containing at most one BMC per chassis.
+// Hosts without a chassis in their xname are treated as their own chassis.
+func staggerRounds(hosts []bmcHost) [][]bmcHost {
+	var order []string
+	groups := map[string][]bmcHost{}
+	for _, h := range hosts {
+		key := xname.Chassis(h.Xname)
+		if key == "" {
+			key = h.Host
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], h)
+	}
+	var rounds [][]bmcHost
+	for i := 0; ; i++ {
+		var round []bmcHost
+		for _, k := range order {
+			if i < len(groups[k]) {
+				round = append(round, groups[k][i])
+			}
+		}
+		if len(round) == 0 {
+			return rounds
+		}
+		rounds = append(rounds, round)
+	}
+}
+
+var bmcResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Reset BMCs via Manager.Reset and wait until they are ready again",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		switch rstType {
+		case "GracefulRestart", "ForceRestart":
+		default:
+			return fmt.Errorf("unsupported --reset-type %q (use GracefulRestart|ForceRestart)", rstType)
+		}
+		if rstStagger && !rstWait {
+			return errors.New("--stagger-by-chassis requires --wait")
+		}
+		hosts, err := loadBMCHosts(bmcFile, bmcHostsCSV)
+		if err != nil {
+			return err
+		}
+		if len(hosts) == 0 {
+			return errors.New("no hosts to reset")
+		}
+
+		rounds := [][]bmcHost{hosts}
+		if rstStagger {
+			rounds = staggerRounds(hosts)
+		}
+
+		if bmcDryRun {
+			for i, round := range rounds {
+				labels := make([]string, 0, len(round))
+				for _, h := range round {
+					labels = append(labels, h.label())
+				}
+				fmt.Printf("[dry-run] round %d: would POST Manager.Reset (%s) on %s\n", i+1, rstType, strings.Join(labels, ", "))
+			}
+			return nil
+		}
+
+		user := os.Getenv("REDFISH_USER")
+		pass := os.Getenv("REDFISH_PASSWORD")
+		if user == "" || pass == "" {
+			return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
+		}
+
+		var mu sync.Mutex
+		failed := 0
+		// Chassis with a BMC that did not come back are skipped for the rest of the run
+		// so that a second controller in the same chassis is not taken down.
+		downChassis := map[string]bool{}
+		for i, round := range rounds {
+			if len(rounds) > 1 {
+				fmt.Printf("Round %d/%d: resetting %d BMC(s)\n", i+1, len(rounds), len(round))
+			}
+			runHosts(round, bmcLimits(), func(_ int, h bmcHost) {
+				chassis := xname.Chassis(h.Xname)
+				mu.Lock()
+				if chassis != "" && downChassis[chassis] {
+					fmt.Fprintf(os.Stderr, "WARN: %s: skipped, another BMC in %s did not become ready\n", h.label(), chassis)
+					failed++
+					mu.Unlock()
+					return
+				}
+				mu.Unlock()
+
+				err := resetAndWait(cmd.Context(), h.Host, user, pass)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", h.label(), err)
+					failed++
+					if chassis != "" {
+						downChassis[chassis] = true
+					}
+					return
+				}
+				if rstWait {
+					fmt.Printf("%s: BMC is ready\n", h.label())
+				} else {
+					fmt.Printf("Triggered %s on %s\n", rstType, h.label())
+				}
+			})
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d BMC(s) failed to reset or become ready", failed, len(hosts))
+		}
+		return nil
+	},
+}
+
+// resetAndWait posts Manager.Reset and, when --wait is set, blocks until the BMC reports Enabled/OK.
+func resetAndWait(parent context.Context, host, user, pass string) error {
+	ctx := parent
+	if bmcTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, bmcTimeout)
+		defer cancel()
+	}
+	if err := redfish.ResetManager(ctx, host, user, pass, bmcInsecure, bmcTimeout, rstType); err != nil {
+		return fmt.Errorf("reset: %w", err)
+	}
+	if !rstWait {
+		return nil
+	}
+
+	waitCtx := parent
+	if rstWaitTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(parent, rstWaitTimeout)
+		defer cancel()
+	}
+	// Give the BMC time to actually go down before polling, otherwise the
+	// first poll may be answered by the controller that is about to reboot.
+	select {
+	case <-waitCtx.Done():
+		return fmt.Errorf("wait for ready: %w", waitCtx.Err())
+	case <-time.After(rstSettle):
+	}
+	if err := redfish.WaitManagerReady(waitCtx, host, user, pass, bmcInsecure, bmcTimeout, rstInterval); err != nil {
+		return fmt.Errorf("wait for ready: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(bmcCmd)
+	bmcCmd.AddCommand(bmcResetCmd)
+	bmcCmd.PersistentFlags().StringVarP(&bmcFile, "file", "f", "", "Inventory file to read bmcs[] from when --hosts is not provided")
+	bmcCmd.PersistentFlags().StringVar(&bmcHostsCSV, "hosts", "", "Comma-separated list of BMC hosts to target (overrides --file)")
+	bmcCmd.PersistentFlags().BoolVar(&bmcInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	bmcCmd.PersistentFlags().DurationVar(&bmcTimeout, "timeout", 30*time.Second, "per-BMC request timeout")
+	bmcCmd.PersistentFlags().BoolVar(&bmcDryRun, "dry-run", false, "plan only: print actions without contacting BMCs")
+	bmcCmd.PersistentFlags().IntVar(&bmcBatchSize, "batch-size", 0, "maximum concurrent BMCs (0 = no limit)")
+	bmcCmd.PersistentFlags().IntVar(&bmcMaxPerCabinet, "max-per-cabinet", 0, "maximum concurrent BMCs per cabinet, from bmcs[] xnames (0 = no limit)")
+	bmcCmd.PersistentFlags().IntVar(&bmcMaxPerChassis, "max-per-chassis", 0, "maximum concurrent BMCs per chassis, from bmcs[] xnames (0 = no limit)")
+
+	bmcResetCmd.Flags().StringVar(&rstType, "reset-type", "GracefulRestart", "Manager.Reset ResetType: GracefulRestart|ForceRestart")
+	bmcResetCmd.Flags().BoolVar(&rstWait, "wait", true, "wait until each BMC answers again with Manager Status Enabled/OK")
+	bmcResetCmd.Flags().DurationVar(&rstWaitTimeout, "wait-timeout", 10*time.Minute, "maximum time to wait for each BMC to become ready")
+	bmcResetCmd.Flags().DurationVar(&rstInterval, "interval", 10*time.Second, "poll interval while waiting for readiness")
+	bmcResetCmd.Flags().DurationVar(&rstSettle, "settle", 30*time.Second, "delay after the reset before polling starts")
+	bmcResetCmd.Flags().BoolVar(&rstStagger, "stagger-by-chassis", false, "reset at most one BMC per chassis at a time, waiting for each round before the next")
+}
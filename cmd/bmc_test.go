@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// runCmd runs cmd with stdout and stderr redirected to a pipe and returns what it printed.
+// The pipe is drained while the command runs so that long output cannot block it.
+func runCmd(t *testing.T, cmd *cobra.Command) (string, error) {
+	t.Helper()
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- b
+	}()
+	os.Stdout, os.Stderr = w, w
+	cmd.SetContext(context.Background())
+	runErr := cmd.RunE(cmd, []string{})
+	w.Close() //nolint:errcheck
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	return string(<-out), runErr
+}
+
+func TestStaggerRounds(t *testing.T) {
+	hosts := []bmcHost{
+		{Xname: "x9000c1s0b0", Host: "10.0.0.1"},
+		{Xname: "x9000c1s0b1", Host: "10.0.0.2"},
+		{Xname: "x9000c3s0b0", Host: "10.0.0.3"},
+		{Xname: "x9000c1s1b0", Host: "10.0.0.4"},
+		{Host: "10.0.0.5"},
+	}
+	got := staggerRounds(hosts)
+	want := [][]bmcHost{
+		{hosts[0], hosts[2], hosts[4]},
+		{hosts[1]},
+		{hosts[3]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("staggerRounds mismatch:\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestBMCResetWaitsForReady(t *testing.T) {
+	var resets, managerGets int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/Actions/Manager.Reset"):
+			atomic.AddInt32(&resets, 1)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/redfish/v1":
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/redfish/v1/Managers":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`))
+		case r.URL.Path == "/redfish/v1/Managers/BMC":
+			if atomic.LoadInt32(&resets) > 0 && atomic.AddInt32(&managerGets, 1) < 3 {
+				_, _ = w.Write([]byte(`{"Status":{"State":"Starting","Health":"OK"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Status":{"State":"Enabled","Health":"OK"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	bmcFile = ""
+	bmcHostsCSV = strings.TrimPrefix(server.URL, "https://")
+	bmcInsecure = true
+	bmcTimeout = 2 * time.Second
+	bmcDryRun = false
+	rstType = "ForceRestart"
+	rstWait = true
+	rstWaitTimeout = 5 * time.Second
+	rstInterval = 10 * time.Millisecond
+	rstSettle = 0
+	rstStagger = false
+
+	out, err := runCmd(t, bmcResetCmd)
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&resets); got != 1 {
+		t.Fatalf("expected 1 reset, got %d", got)
+	}
+	if !strings.Contains(string(out), "BMC is ready") {
+		t.Fatalf("expected ready message, got:\n%s", out)
+	}
+}
@@ -16,7 +16,6 @@ import (
 	"bootstrap/internal/redfish"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -54,14 +53,10 @@ var discoverCmd = &cobra.Command{
 			return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
 		}
 
-		raw, err := os.ReadFile(discFile)
+		doc, err := loadInventory(discFile)
 		if err != nil {
 			return err
 		}
-		var doc inventory.FileFormat
-		if err := yaml.Unmarshal(raw, &doc); err != nil {
-			return err
-		}
 		if len(doc.BMCs) == 0 {
 			return fmt.Errorf("input must contain non-empty bmcs[]")
 		}
@@ -112,12 +107,12 @@ var discoverCmd = &cobra.Command{
 			}
 		}
 
-		nodes, err := discover.UpdateNodes(&doc, discBMCSubnet, discNodeSubnet, discNodeStartIP, user, pass, discInsecure, discTimeout)
+		nodes, err := discover.UpdateNodes(doc, discBMCSubnet, discNodeSubnet, discNodeStartIP, user, pass, discInsecure, discTimeout)
 		if err != nil {
 			return err
 		}
 		doc.Nodes = nodes
-		bytes, err := yaml.Marshal(&doc)
+		bytes, err := inventory.Marshal(doc)
 		if err != nil {
 			return err
 		}
@@ -131,7 +126,7 @@ var discoverCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(discoverCmd)
-	discoverCmd.Flags().StringVarP(&discFile, "file", "f", "", "YAML file containing bmcs[] and nodes[] (nodes will be overwritten)")
+	discoverCmd.Flags().StringVarP(&discFile, "file", "f", "", "YAML file containing bmcs[] and nodes[] (discovered nodes are updated, others are kept)")
 	discoverCmd.Flags().StringVar(&discBMCSubnet, "bmc-subnet", "", "CIDR for BMC IPs, e.g. 192.168.100.0/24 (if not specified, uses --node-subnet)")
 	discoverCmd.Flags().StringVar(&discNodeSubnet, "node-subnet", "", "CIDR for node IPs, e.g. 10.42.0.0/24 (if not specified, uses --bmc-subnet)")
 	discoverCmd.Flags().StringVar(&discNodeStartIP, "node-start-ip", "", "Start node IP allocation at this address (skips all IPs before it)")
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expFile   string
+	expOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Generate configuration for other services from an inventory file",
+}
+
+// writeExport writes generated output to --output, or to stdout when it is empty.
+func writeExport(b []byte) error {
+	if expOutput == "" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(expOutput, b, 0o644)
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.PersistentFlags().StringVarP(&expFile, "file", "f", "", "inventory YAML file (required)")
+	exportCmd.PersistentFlags().StringVarP(&expOutput, "output", "o", "", "write to this file instead of stdout")
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"bootstrap/internal/ansible"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expAnsibleFormat string
+	expList          bool
+	expHost          string
+)
+
+var exportAnsibleCmd = &cobra.Command{
+	Use:   "ansible",
+	Short: "Generate an Ansible inventory (INI, YAML or dynamic inventory JSON)",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if expFile == "" {
+			return errors.New("--file is required")
+		}
+		if expList && expHost != "" {
+			return errors.New("--list and --host are mutually exclusive")
+		}
+		doc, err := loadInventory(expFile)
+		if err != nil {
+			return err
+		}
+		inv := ansible.Build(doc)
+		var out []byte
+		switch {
+		case expHost != "":
+			out, err = inv.Host(expHost)
+		case expList || expAnsibleFormat == "json":
+			out, err = inv.List()
+		case expAnsibleFormat == "ini":
+			out = inv.INI()
+		case expAnsibleFormat == "yaml":
+			out, err = inv.YAML()
+		default:
+			return fmt.Errorf("unknown --format %q (use ini|yaml|json)", expAnsibleFormat)
+		}
+		if err != nil {
+			return err
+		}
+		return writeExport(out)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportAnsibleCmd)
+	exportAnsibleCmd.Flags().StringVar(&expAnsibleFormat, "format", "ini", "output format: ini|yaml|json (dynamic inventory)")
+	exportAnsibleCmd.Flags().BoolVar(&expList, "list", false, "dynamic inventory: print every group and host as JSON")
+	exportAnsibleCmd.Flags().StringVar(&expHost, "host", "", "dynamic inventory: print the variables of one host as JSON")
+}
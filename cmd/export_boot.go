@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"bootstrap/internal/boot"
+	"bootstrap/internal/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expBootConfig   string
+	expBootFormat   string
+	expCloudInitDir string
+)
+
+var exportBootCmd = &cobra.Command{
+	Use:   "boot",
+	Short: "Generate iPXE scripts, GRUB configs and cloud-init NoCloud data for every node",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if expFile == "" {
+			return errors.New("--file is required")
+		}
+		if expBootConfig == "" {
+			return errors.New("--config is required")
+		}
+		switch expBootFormat {
+		case "ipxe", "grub":
+			if expOutputDir == "" {
+				return fmt.Errorf("--format %s writes one file per node; --output-dir is required", expBootFormat)
+			}
+		case "ipxe-single":
+		default:
+			return fmt.Errorf("unknown --format %q (use ipxe|ipxe-single|grub)", expBootFormat)
+		}
+		conf, err := boot.Load(expBootConfig)
+		if err != nil {
+			return err
+		}
+		doc, err := loadInventory(expFile)
+		if err != nil {
+			return err
+		}
+		nodes, err := bootNodes(conf, doc)
+		if err != nil {
+			return err
+		}
+
+		switch expBootFormat {
+		case "ipxe-single":
+			err = writeExport([]byte(boot.IPXEAll(nodes)))
+		case "ipxe":
+			err = writeBootFiles(nodes, boot.MACName, ".ipxe", boot.IPXE)
+			if err == nil {
+				err = os.WriteFile(filepath.Join(expOutputDir, "boot.ipxe"), []byte(boot.ChainIPXE), 0o644)
+			}
+		case "grub":
+			err = writeBootFiles(nodes, boot.GRUBName, "", boot.GRUB)
+		}
+		if err != nil {
+			return err
+		}
+		if expCloudInitDir != "" {
+			return writeCloudInit(nodes)
+		}
+		return nil
+	},
+}
+
+// bootNodes renders the boot parameters of every node with a MAC. Nodes without one
+// cannot be matched by a boot loader and are skipped with a warning.
+func bootNodes(conf *boot.Config, doc *inventory.FileFormat) ([]boot.Node, error) {
+	var nodes []boot.Node
+	for _, n := range doc.Nodes {
+		if n.MAC == "" {
+			fmt.Fprintf(os.Stderr, "WARN: %s: no MAC, skipped\n", n.Xname)
+			continue
+		}
+		p, err := conf.Render(n)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, boot.Node{Entry: n, Params: p})
+	}
+	return nodes, nil
+}
+
+// writeBootFiles writes one file per node into --output-dir, named after its MAC.
+func writeBootFiles(nodes []boot.Node, name func(string) string, ext string, render func(inventory.Entry, boot.Params) string) error {
+	if err := os.MkdirAll(expOutputDir, 0o755); err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		path := filepath.Join(expOutputDir, name(n.Entry.MAC)+ext)
+		if err := os.WriteFile(path, []byte(render(n.Entry, n.Params)), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", n.Entry.Xname, path)
+	}
+	return nil
+}
+
+// writeCloudInit writes a NoCloud meta-data and user-data pair per node into
+// --cloud-init-dir/<xname>/.
+func writeCloudInit(nodes []boot.Node) error {
+	for _, n := range nodes {
+		dir := filepath.Join(expCloudInitDir, n.Entry.Xname)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "meta-data"), []byte(n.Params.MetaData), 0o644); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "user-data"), []byte(n.Params.UserData), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	exportCmd.AddCommand(exportBootCmd)
+	exportBootCmd.Flags().StringVar(&expBootConfig, "config", "", "boot parameters YAML with kernel, initrd and params templates and role/group profiles (required)")
+	exportBootCmd.Flags().StringVar(&expBootFormat, "format", "ipxe", "output format: ipxe (one script per MAC plus boot.ipxe)|ipxe-single (one script branching on ${mac})|grub (grub.cfg-01-<mac> per node)")
+	exportBootCmd.Flags().StringVar(&expOutputDir, "output-dir", "", "ipxe, grub: directory to write the per-node files to")
+	exportBootCmd.Flags().StringVar(&expCloudInitDir, "cloud-init-dir", "", "also write NoCloud meta-data and user-data to DIR/<xname>/")
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"bootstrap/internal/dhcp"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expDHCPFormat string
+	expSubnets    string
+)
+
+var exportDHCPCmd = &cobra.Command{
+	Use:   "dhcp",
+	Short: "Generate dnsmasq or Kea DHCPv4 reservations for every BMC and node",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if expFile == "" {
+			return errors.New("--file is required")
+		}
+		doc, err := loadInventory(expFile)
+		if err != nil {
+			return err
+		}
+		var subnets *dhcp.File
+		if expSubnets != "" {
+			if subnets, err = dhcp.Load(expSubnets); err != nil {
+				return err
+			}
+		}
+		res := dhcp.Reservations(doc)
+		var out []byte
+		switch expDHCPFormat {
+		case "dnsmasq":
+			var b bytes.Buffer
+			if err := dhcp.Dnsmasq(&b, res, subnets); err != nil {
+				return err
+			}
+			out = b.Bytes()
+		case "kea":
+			if out, err = dhcp.Kea(res, subnets); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown --format %q (use dnsmasq|kea)", expDHCPFormat)
+		}
+		return writeExport(out)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportDHCPCmd)
+	exportDHCPCmd.Flags().StringVar(&expDHCPFormat, "format", "dnsmasq", "output format: dnsmasq|kea")
+	exportDHCPCmd.Flags().StringVar(&expSubnets, "subnets", "", "YAML file with per-subnet options (router, dns, domain, next_server, filename)")
+}
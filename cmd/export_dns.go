@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bootstrap/internal/dns"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expDNSFormat    string
+	expDomain       string
+	expBMCNames     []string
+	expNodeNames    []string
+	expIfaceName    string
+	expOutputDir    string
+	expNameserver   string
+	expNameserverIP string
+	expAdmin        string
+	expTTL          time.Duration
+)
+
+var exportDNSCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Generate /etc/hosts, BIND zone files or a CoreDNS hosts file for every BMC and node",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if expFile == "" {
+			return errors.New("--file is required")
+		}
+		doc, err := loadInventory(expFile)
+		if err != nil {
+			return err
+		}
+		hosts, err := dns.Hosts(doc, dns.Templates{BMC: expBMCNames, Node: expNodeNames, Interface: expIfaceName})
+		if err != nil {
+			return err
+		}
+		var b bytes.Buffer
+		switch expDNSFormat {
+		case "hosts":
+			err = dns.HostsFile(&b, hosts, expDomain)
+		case "coredns":
+			err = dns.CoreDNS(&b, hosts, expDomain)
+		case "bind":
+			return exportZones(hosts)
+		default:
+			return fmt.Errorf("unknown --format %q (use hosts|bind|coredns)", expDNSFormat)
+		}
+		if err != nil {
+			return err
+		}
+		return writeExport(b.Bytes())
+	},
+}
+
+// exportZones writes one db.<zone> file per zone into --output-dir, keeping the SOA serial
+// of zones that did not change, and prints the named.conf zone statements. Without
+// --output-dir the zones are printed to stdout.
+func exportZones(hosts []dns.Host) error {
+	if expOutput != "" {
+		return errors.New("--format bind writes one file per zone; use --output-dir instead of --output")
+	}
+	opts := dns.ZoneOptions{Domain: expDomain, Nameserver: expNameserver, NameserverIP: expNameserverIP, Admin: expAdmin, TTL: expTTL}
+	zones, err := dns.Zones(hosts, opts)
+	if errors.Is(err, dns.ErrNameserverAddress) {
+		return fmt.Errorf("%w; pass --nameserver-ip, or a --nameserver outside --domain (ending in a dot)", err)
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, z := range zones {
+		if expOutputDir == "" {
+			fmt.Printf("; zone %s\n%s\n", z.Name, z.Render(opts, z.Serial(opts, nil, now)))
+			continue
+		}
+		path := filepath.Join(expOutputDir, "db."+z.Name)
+		previous, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(z.Render(opts, z.Serial(opts, previous, now))), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("zone \"%s\" { type master; file \"%s\"; };\n", z.Name, path)
+	}
+	return nil
+}
+
+func init() {
+	exportCmd.AddCommand(exportDNSCmd)
+	exportDNSCmd.Flags().StringVar(&expDNSFormat, "format", "hosts", "output format: hosts|bind|coredns")
+	exportDNSCmd.Flags().StringVar(&expDomain, "domain", "", "DNS domain, e.g. cluster.local (required for bind)")
+	exportDNSCmd.Flags().StringArrayVar(&expBMCNames, "bmc-name", dns.DefaultTemplates.BMC, "name template for BMCs, repeatable; the first non-empty name is canonical, the others are aliases")
+	exportDNSCmd.Flags().StringArrayVar(&expNodeNames, "node-name", dns.DefaultTemplates.Node, `name template for nodes, repeatable, e.g. 'nid{{printf "%06d" .NID}}'`)
+	exportDNSCmd.Flags().StringVar(&expIfaceName, "interface-name", dns.DefaultTemplates.Interface, "name template for extra interfaces with an IP ({{.Interface}}, {{.Network}}); empty skips them")
+	exportDNSCmd.Flags().StringVar(&expOutputDir, "output-dir", "", "bind: directory to write db.<zone> files to")
+	exportDNSCmd.Flags().StringVar(&expNameserver, "nameserver", "ns1", "bind: primary name server for SOA/NS, relative to --domain unless it ends in a dot")
+	exportDNSCmd.Flags().StringVar(&expNameserverIP, "nameserver-ip", "", "bind: address record for --nameserver, required when it is inside --domain and not an inventory host name")
+	exportDNSCmd.Flags().StringVar(&expAdmin, "admin", "hostmaster", "bind: SOA responsible mailbox, relative to --domain unless it ends in a dot")
+	exportDNSCmd.Flags().DurationVar(&expTTL, "ttl", time.Hour, "bind: default TTL and negative caching TTL")
+}
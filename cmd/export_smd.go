@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"bootstrap/internal/smd"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expSMDURL      string
+	expInsecure    bool
+	expTimeout     time.Duration
+	expCredentials bool
+)
+
+var exportSMDCmd = &cobra.Command{
+	Use:   "smd",
+	Short: "Generate OpenCHAMI SMD RedfishEndpoints, Components, ComponentEndpoints and EthernetInterfaces",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if expFile == "" {
+			return errors.New("--file is required")
+		}
+		doc, err := loadInventory(expFile)
+		if err != nil {
+			return err
+		}
+		var creds smd.Credentials
+		if expCredentials {
+			creds.User, creds.Password = os.Getenv("REDFISH_USER"), os.Getenv("REDFISH_PASSWORD")
+			if creds.User == "" || creds.Password == "" {
+				return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required with --redfish-credentials")
+			}
+		}
+		payload := smd.FromInventory(doc, creds)
+
+		if expSMDURL == "" || expOutput != "" {
+			out, err := json.MarshalIndent(payload, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := writeExport(append(out, '\n')); err != nil {
+				return err
+			}
+		}
+		if expSMDURL == "" {
+			return nil
+		}
+		c := smd.NewClient(expSMDURL, os.Getenv("SMD_TOKEN"), expInsecure, expTimeout)
+		if err := c.Post(cmd.Context(), payload); err != nil {
+			return err
+		}
+		fmt.Printf("Posted %d RedfishEndpoint(s), %d Component(s) and %d EthernetInterface(s) to %s (SMD creates ComponentEndpoints when it discovers the endpoints)\n",
+			len(payload.RedfishEndpoints), len(payload.Components), len(payload.EthernetInterfaces), expSMDURL)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportSMDCmd)
+	exportSMDCmd.Flags().StringVar(&expSMDURL, "smd-url", "", "POST the objects to the SMD at this base URL, e.g. https://smd.example:27779 (bearer token from SMD_TOKEN)")
+	exportSMDCmd.Flags().BoolVar(&expInsecure, "insecure", false, "allow insecure TLS to SMD")
+	exportSMDCmd.Flags().DurationVar(&expTimeout, "timeout", 30*time.Second, "per-request SMD timeout")
+	exportSMDCmd.Flags().BoolVar(&expCredentials, "redfish-credentials", false, "include REDFISH_USER/REDFISH_PASSWORD in RedfishEndpoints so SMD can discover the BMCs")
+}
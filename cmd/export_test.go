@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/internal/smd"
+)
+
+const exportInventory = `bmcs:
+    - xname: x9000c1s0b0
+      mac: "02:23:28:01:30:00"
+      ip: 192.168.100.1
+nodes:
+    - xname: x9000c1s0b0n0
+      mac: aa:bb:cc:dd:ee:01
+      ip: 10.42.0.1
+      nid: 1
+      hostname: nid000001
+      role: Compute
+      groups: [batch]
+      interfaces:
+        - {name: eth0, mac: "aa:bb:cc:dd:ee:01", ip: 10.42.0.1, network: nmn}
+`
+
+// configureExport writes exportInventory and resets the export globals.
+func configureExport(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	expFile = filepath.Join(dir, "inventory.yaml")
+	if err := os.WriteFile(expFile, []byte(exportInventory), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expOutput, expSMDURL, expInsecure, expTimeout, expCredentials = "", "", false, 5*time.Second, false
+	expDHCPFormat, expSubnets = "dnsmasq", ""
+	expDNSFormat, expDomain, expIfaceName, expOutputDir = "hosts", "", "{{.Xname}}-{{.Interface}}", ""
+	expBMCNames, expNodeNames = []string{"{{.Xname}}"}, []string{"{{.Xname}}", "{{.Hostname}}"}
+	expNameserver, expNameserverIP, expAdmin, expTTL = "ns1", "", "hostmaster", time.Hour
+	expAnsibleFormat, expList, expHost = "ini", false, ""
+	expBootConfig, expBootFormat, expCloudInitDir = "../examples/boot.yaml", "ipxe", ""
+	return dir
+}
+
+func TestExportSMD(t *testing.T) {
+	dir := configureExport(t)
+	expOutput = filepath.Join(dir, "smd.json")
+	if out, err := runCmd(t, exportSMDCmd); err != nil {
+		t.Fatalf("export: %v\n%s", err, out)
+	}
+	raw, err := os.ReadFile(expOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p smd.Payload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		t.Fatalf("output is not a payload: %v\n%s", err, raw)
+	}
+	if len(p.RedfishEndpoints) != 1 || len(p.Components) != 1 || len(p.ComponentEndpoints) != 1 || len(p.EthernetInterfaces) != 2 {
+		t.Fatalf("unexpected payload: %s", raw)
+	}
+
+	// POST to a stub SMD that only accepts the requests the SMD API has
+	var mu sync.Mutex
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		req := r.Method + " " + r.URL.Path
+		mu.Lock()
+		posted = append(posted, req)
+		mu.Unlock()
+		switch req {
+		case "POST " + smd.RedfishEndpointsPath, "POST " + smd.ComponentsPath, "POST " + smd.EthernetInterfacesPath:
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "unexpected "+req, http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("SMD_TOKEN", "tok")
+	expOutput, expSMDURL = "", server.URL
+	out, err := runCmd(t, exportSMDCmd)
+	if err != nil {
+		t.Fatalf("post: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Posted 1 RedfishEndpoint(s), 1 Component(s) and 2 EthernetInterface(s)") || strings.Contains(out, `"RedfishEndpoints"`) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	want := "POST /hsm/v2/Inventory/RedfishEndpoints,POST /hsm/v2/State/Components,POST /hsm/v2/Inventory/EthernetInterfaces,POST /hsm/v2/Inventory/EthernetInterfaces"
+	if got := strings.Join(posted, ","); got != want {
+		t.Errorf("requests = %s", got)
+	}
+}
+
+func TestExportDHCP(t *testing.T) {
+	configureExport(t)
+	expSubnets = "../examples/subnets.yaml"
+	out, err := runCmd(t, exportDHCPCmd)
+	if err != nil {
+		t.Fatalf("dnsmasq: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"dhcp-boot=tag:nmn,ipxe.efi,,10.42.0.2\n",
+		"dhcp-host=02:23:28:01:30:00,192.168.100.1,x9000c1s0b0\n",
+		"dhcp-host=aa:bb:cc:dd:ee:01,10.42.0.1,x9000c1s0b0n0\nhost-record=x9000c1s0b0n0,10.42.0.1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dnsmasq output missing %q:\n%s", want, out)
+		}
+	}
+
+	expDHCPFormat = "kea"
+	if out, err = runCmd(t, exportDHCPCmd); err != nil {
+		t.Fatalf("kea: %v\n%s", err, out)
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(out), &cfg); err != nil || !strings.Contains(out, `"hw-address": "aa:bb:cc:dd:ee:01"`) {
+		t.Errorf("kea output (%v):\n%s", err, out)
+	}
+
+	expDHCPFormat = "isc"
+	if _, err = runCmd(t, exportDHCPCmd); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestExportDNS(t *testing.T) {
+	dir := configureExport(t)
+	expDomain, expBMCNames = "cluster.local", []string{"{{.Xname}}-bmc"}
+	out, err := runCmd(t, exportDNSCmd)
+	if err != nil {
+		t.Fatalf("hosts: %v\n%s", err, out)
+	}
+	want := "192.168.100.1\tx9000c1s0b0-bmc.cluster.local\tx9000c1s0b0-bmc\n10.42.0.1\tx9000c1s0b0n0.cluster.local\tx9000c1s0b0n0\tnid000001\n"
+	if out != want {
+		t.Fatalf("hosts output:\n%q\nwant:\n%q", out, want)
+	}
+
+	expDNSFormat, expOutputDir = "bind", dir
+	if _, err = runCmd(t, exportDNSCmd); err == nil || !strings.Contains(err.Error(), "ns1.cluster.local: name server inside the domain has no address record; pass --nameserver-ip") {
+		t.Fatalf("expected the in-domain nameserver without an address to be rejected, got %v", err)
+	}
+	expNameserverIP = "10.42.0.2"
+	if out, err = runCmd(t, exportDNSCmd); err != nil {
+		t.Fatalf("bind: %v\n%s", err, out)
+	}
+	fwd := filepath.Join(dir, "db.cluster.local")
+	if !strings.Contains(out, `zone "cluster.local" { type master; file "`+fwd+`"; };`) || !strings.Contains(out, "db.0.42.10.in-addr.arpa") {
+		t.Errorf("zone statements:\n%s", out)
+	}
+	first, err := os.ReadFile(fwd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(first), "nid000001                IN A    10.42.0.1\n") || !strings.Contains(string(first), "ns1                      IN A    10.42.0.2\n") {
+		t.Errorf("forward zone:\n%s", first)
+	}
+	// Unchanged zones keep their serial
+	if out, err = runCmd(t, exportDNSCmd); err != nil {
+		t.Fatalf("bind again: %v\n%s", err, out)
+	}
+	if again, _ := os.ReadFile(fwd); string(again) != string(first) {
+		t.Errorf("unchanged zone was rewritten differently:\n%s", again)
+	}
+
+	expOutput = filepath.Join(dir, "zones")
+	if _, err = runCmd(t, exportDNSCmd); err == nil {
+		t.Error("expected --output to be rejected for bind")
+	}
+}
+
+func TestExportAnsible(t *testing.T) {
+	configureExport(t)
+	out, err := runCmd(t, exportAnsibleCmd)
+	if err != nil {
+		t.Fatalf("ini: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"[nodes]\nnid000001 ansible_host=10.42.0.1 bmc_ip=192.168.100.1 bmc_xname=x9000c1s0b0 mac=aa:bb:cc:dd:ee:01 nid=1 role=Compute xname=x9000c1s0b0n0\n",
+		"[label_batch]\nnid000001\n",
+		"[chassis_x9000c1]\nnid000001\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("INI missing %q:\n%s", want, out)
+		}
+	}
+
+	// Dynamic inventory, as Ansible calls it through a wrapper script
+	expList = true
+	if out, err = runCmd(t, exportAnsibleCmd); err != nil {
+		t.Fatalf("--list: %v\n%s", err, out)
+	}
+	var list struct {
+		Meta struct {
+			HostVars map[string]map[string]any `json:"hostvars"`
+		} `json:"_meta"`
+		RoleCompute struct{ Hosts []string } `json:"role_compute"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil || list.Meta.HostVars["nid000001"]["ansible_host"] != "10.42.0.1" || len(list.RoleCompute.Hosts) != 1 {
+		t.Fatalf("--list (%v):\n%s", err, out)
+	}
+	expList, expHost = false, "nid000001"
+	if out, err = runCmd(t, exportAnsibleCmd); err != nil || !strings.Contains(out, `"bmc_ip": "192.168.100.1"`) {
+		t.Fatalf("--host (%v):\n%s", err, out)
+	}
+}
+
+func TestExportBoot(t *testing.T) {
+	dir := configureExport(t)
+	expOutputDir, expCloudInitDir = filepath.Join(dir, "tftp"), filepath.Join(dir, "cloud-init")
+	if out, err := runCmd(t, exportBootCmd); err != nil {
+		t.Fatalf("ipxe: %v\n%s", err, out)
+	}
+	script, err := os.ReadFile(filepath.Join(expOutputDir, "aa-bb-cc-dd-ee-01.ipxe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(script), "kernel http://10.42.0.2:8080/boot/vmlinuz initrd=initrd console=ttyS0,115200") {
+		t.Errorf("per-MAC script:\n%s", script)
+	}
+	if _, err := os.Stat(filepath.Join(expOutputDir, "boot.ipxe")); err != nil {
+		t.Error(err)
+	}
+	meta, err := os.ReadFile(filepath.Join(expCloudInitDir, "x9000c1s0b0n0", "meta-data"))
+	if err != nil || string(meta) != "instance-id: x9000c1s0b0n0\nlocal-hostname: nid000001\n" {
+		t.Errorf("meta-data (%v):\n%s", err, meta)
+	}
+
+	expBootFormat = "grub"
+	if out, err := runCmd(t, exportBootCmd); err != nil {
+		t.Fatalf("grub: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(expOutputDir, "grub.cfg-01-aa-bb-cc-dd-ee-01")); err != nil {
+		t.Error(err)
+	}
+
+	expBootFormat, expOutputDir, expCloudInitDir = "ipxe-single", "", ""
+	out, err := runCmd(t, exportBootCmd)
+	if err != nil || !strings.Contains(out, "iseq ${mac} aa:bb:cc:dd:ee:01 && goto x9000c1s0b0n0 ||") {
+		t.Fatalf("ipxe-single (%v):\n%s", err, out)
+	}
+	expBootFormat = "grub"
+	if _, err := runCmd(t, exportBootCmd); err == nil || !strings.Contains(err.Error(), "--output-dir is required") {
+		t.Errorf("expected --output-dir error, got %v", err)
+	}
+}
@@ -5,19 +5,25 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
-	"bootstrap/internal/inventory"
+	"bootstrap/internal/baseline"
+	"bootstrap/internal/fanout"
+	"bootstrap/internal/fwimage"
+	"bootstrap/internal/journal"
 	"bootstrap/internal/redfish"
+	"bootstrap/internal/rollout"
+	"bootstrap/internal/version"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -33,8 +39,99 @@ var (
 	fwForce           bool
 	fwExpectedVersion string
 	fwBatchSize       int
+	fwMaxPerCabinet   int
+	fwMaxPerChassis   int
+	fwApplyTime       string
+	fwWindowStart     string
+	fwWindowDuration  time.Duration
+	fwPushURIApply    bool
+	fwVersionScheme   string
+	fwMinVersion      string
+	fwMaxVersion      string
+	fwAllowDowngrade  bool
+	fwPreflight       bool
+	fwSHA256          string
+	fwSHA512          string
+	fwJournal         string
+	fwResume          bool
+
+	fwCanary           int
+	fwWaveBy           string
+	fwFailureThreshold string
+	fwWaveTimeout      time.Duration
+	fwWaveInterval     time.Duration
+	fwConfirmWaves     bool
 )
 
+// applyTimeOptions builds the SimpleUpdate apply time settings from the --apply-time and
+// --maintenance-window-* flags.
+func applyTimeOptions() (redfish.ApplyTimeOptions, error) {
+	var opts redfish.ApplyTimeOptions
+	if fwApplyTime == "" {
+		if fwWindowStart != "" || fwWindowDuration > 0 {
+			return opts, errors.New("--maintenance-window-start/--maintenance-window-duration require --apply-time")
+		}
+		return opts, nil
+	}
+	applyTime, err := redfish.ParseApplyTime(fwApplyTime)
+	if err != nil {
+		return opts, err
+	}
+	opts.ApplyTime = applyTime
+	opts.SetPushURIApplyTime = fwPushURIApply
+	if fwWindowStart != "" {
+		opts.WindowStart, err = time.Parse(time.RFC3339, fwWindowStart)
+		if err != nil {
+			return opts, fmt.Errorf("--maintenance-window-start: %w", err)
+		}
+	}
+	opts.WindowDuration = fwWindowDuration
+	windowed := applyTime == redfish.ApplyAtMaintenanceWindowStart || applyTime == redfish.ApplyInMaintenanceWindowOnReset
+	if !windowed && (fwWindowStart != "" || fwWindowDuration > 0) {
+		return opts, fmt.Errorf("maintenance window flags are only valid with --apply-time %s or %s",
+			redfish.ApplyAtMaintenanceWindowStart, redfish.ApplyInMaintenanceWindowOnReset)
+	}
+	return opts, nil
+}
+
+// pushURIHint points at --set-push-uri-apply-time when the BMC only takes the apply time
+// through its BMC-wide HttpPushUriOptions.
+func pushURIHint(err error) error {
+	if errors.Is(err, redfish.ErrPushURIApplyTime) {
+		return fmt.Errorf("%w; pass --set-push-uri-apply-time to change it anyway", err)
+	}
+	return err
+}
+
+// versionParser returns the parser selected by --version-scheme, falling back to exact
+// matching when the scheme is invalid (commands validate it up front).
+func versionParser() version.Parser {
+	p, err := version.ParseScheme(fwVersionScheme)
+	if err != nil {
+		return version.Exact
+	}
+	return p
+}
+
+// versionPolicy builds the --min-version/--max-version policy using --version-scheme.
+func versionPolicy() (version.Policy, error) {
+	p, err := version.ParseScheme(fwVersionScheme)
+	if err != nil {
+		return version.Policy{}, err
+	}
+	pol, err := version.NewPolicy(p, fwMinVersion, fwMaxVersion)
+	if err != nil {
+		return pol, fmt.Errorf("--min-version/--max-version: %w", err)
+	}
+	return pol, nil
+}
+
+// fwLimits returns the concurrency limits for firmware fan-out: --batch-size overall (serial
+// when 0 or 1), --max-per-cabinet and --max-per-chassis.
+func fwLimits() fanout.Limits {
+	return fanout.Limits{Global: max(1, fwBatchSize), PerCabinet: fwMaxPerCabinet, PerChassis: fwMaxPerChassis}
+}
+
 // defaultTargets returns target list for shorthand types.
 func defaultTargets(t string) ([]string, error) {
 	switch strings.ToLower(t) {
@@ -52,6 +149,35 @@ func defaultTargets(t string) ([]string, error) {
 	}
 }
 
+// resolveTargets expands target patterns (see baseline.MatchTarget) against the
+// FirmwareInventory members of host. Targets that are not patterns are kept as they are.
+func resolveTargets(ctx context.Context, host, user, pass string, targets []string) ([]string, error) {
+	if !slices.ContainsFunc(targets, baseline.IsTargetPattern) {
+		return targets, nil
+	}
+	members, err := redfish.ListFirmwareInventory(ctx, host, user, pass, fwInsecure, fwTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("list FirmwareInventory: %w", err)
+	}
+	var out []string
+	for _, t := range targets {
+		if !baseline.IsTargetPattern(t) {
+			out = append(out, t)
+			continue
+		}
+		n := len(out)
+		for _, m := range members {
+			if baseline.MatchTarget(t, m) && !slices.Contains(out, m) {
+				out = append(out, m)
+			}
+		}
+		if len(out) == n {
+			return nil, fmt.Errorf("no FirmwareInventory member matches %s", t)
+		}
+	}
+	return out, nil
+}
+
 var firmwareCmd = &cobra.Command{
 	Use:   "firmware",
 	Short: "Update firmware via Redfish SimpleUpdate",
@@ -59,8 +185,15 @@ var firmwareCmd = &cobra.Command{
 		if fwFile == "" && fwHostsCSV == "" {
 			return errors.New("at least one of --file or --hosts is required")
 		}
-		if fwImageURI == "" {
-			return errors.New("--image-uri is required")
+		switch {
+		case fwImageURI == "" && fwCatalog == "":
+			return errors.New("--image-uri or --catalog is required")
+		case fwImageURI != "" && fwCatalog != "":
+			return errors.New("--image-uri and --catalog are mutually exclusive")
+		case fwCatalog != "" && fwType == "":
+			return errors.New("--catalog requires --type to pick the component")
+		case fwCatalog != "" && (fwSHA256 != "" || fwSHA512 != ""):
+			return errors.New("--sha256 and --sha512 cannot be used with --catalog; put sha256 in the catalog instead")
 		}
 		if len(fwTargets) == 0 {
 			if fwType == "" {
@@ -73,139 +206,458 @@ var firmwareCmd = &cobra.Command{
 			}
 		}
 
+		applyOpts, err := applyTimeOptions()
+		if err != nil {
+			return err
+		}
+		if err := checkPowerFlags(); err != nil {
+			return err
+		}
+		if fwVerify && applyOpts.ApplyTime != "" && applyOpts.ApplyTime != redfish.ApplyImmediate &&
+			(applyOpts.ApplyTime != redfish.ApplyOnReset || fwReboot == rebootNever) {
+			return fmt.Errorf("--verify cannot be used with --apply-time %s: the new version is not running until the image is applied", applyOpts.ApplyTime)
+		}
+		pol, err := versionPolicy()
+		if err != nil {
+			return err
+		}
+		if fwExpectedVersion != "" {
+			if v := pol.Check(fwExpectedVersion); v != "" {
+				return fmt.Errorf("--expected-version %s violates the version policy (%s)", fwExpectedVersion, v)
+			}
+		}
+		groupBy, err := rollout.ParseGroupBy(fwWaveBy)
+		if err != nil {
+			return err
+		}
+		var threshold *rollout.Threshold
+		if strings.TrimSpace(fwFailureThreshold) != "" {
+			th, err := rollout.ParseThreshold(fwFailureThreshold)
+			if err != nil {
+				return err
+			}
+			threshold = &th
+		}
+
 		user := os.Getenv("REDFISH_USER")
 		pass := os.Getenv("REDFISH_PASSWORD")
 		if user == "" || pass == "" {
 			return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
 		}
 
-		// Determine hosts to target
-		hosts := []string{}
-		if strings.TrimSpace(fwHostsCSV) != "" {
-			for _, h := range strings.Split(fwHostsCSV, ",") {
-				h = strings.TrimSpace(h)
-				if h != "" {
-					hosts = append(hosts, h)
-				}
+		if fwResume && fwJournal == "" {
+			return errors.New("--resume requires --journal")
+		}
+
+		if fwImageURI != "" && (fwPreflight || fwSHA256 != "" || fwSHA512 != "") {
+			if err := preflightImage(cmd.Context(), fwImageURI, fwSHA256, fwSHA512); err != nil {
+				return fmt.Errorf("image preflight failed, no BMC was contacted: %w", err)
 			}
-		} else {
-			// Load from inventory file
-			raw, err := os.ReadFile(fwFile)
-			if err != nil {
+		}
+
+		// Determine hosts to target
+		hosts, err := loadBMCHosts(fwFile, fwHostsCSV)
+		if err != nil {
+			return err
+		}
+
+		update := flagUpdate(applyOpts)
+		update.Policy = pol
+		if fwCatalog != "" {
+			if hosts, update.Catalog, err = resolveCatalog(cmd.Context(), hosts, user, pass, pol); err != nil {
 				return err
 			}
-			var doc inventory.FileFormat
-			if err := yaml.Unmarshal(raw, &doc); err != nil {
-				return err
+			if len(hosts) == 0 {
+				return errors.New("no host has a compatible image in --catalog")
 			}
-			if len(doc.BMCs) == 0 {
-				return fmt.Errorf("input must contain non-empty bmcs[]")
+			if err := preflightCatalog(cmd.Context(), update.Catalog); err != nil {
+				return fmt.Errorf("image preflight failed, no update was posted: %w", err)
 			}
-			for _, b := range doc.BMCs {
-				host := b.IP
-				if host == "" {
-					host = b.Xname
-				}
-				hosts = append(hosts, host)
+		}
+
+		jw, err := openJournal()
+		if err != nil {
+			return err
+		}
+		if jw != nil {
+			defer jw.Close() //nolint:errcheck
+		}
+		if fwResume {
+			if hosts, err = resumeHosts(cmd.Context(), hosts, user, pass, update, jw); err != nil {
+				return err
+			}
+			if len(hosts) == 0 {
+				fmt.Println("All hosts already completed according to the journal")
+				return nil
 			}
 		}
 
-		// Apply firmware update to each host
-		if fwBatchSize <= 1 {
-			// Serial execution
-			for _, host := range hosts {
-				ctx := cmd.Context()
-				var cancel context.CancelFunc
-				if fwTimeout > 0 {
-					ctx, cancel = context.WithTimeout(ctx, fwTimeout)
-				}
-				if fwDryRun {
-					dryRunMsg := fmt.Sprintf("[dry-run] would POST SimpleUpdate on %s with image=%s targets=%v protocol=%s",
-						host, fwImageURI, fwTargets, fwProtocol)
-					if fwExpectedVersion != "" {
-						dryRunMsg += fmt.Sprintf(" expected-version=%s", fwExpectedVersion)
-						if fwForce {
-							dryRunMsg += " (force=true)"
-						}
+		// Split hosts into waves (a single wave unless --canary or --wave-by is given)
+		xnames := make([]string, len(hosts))
+		for i, h := range hosts {
+			xnames[i] = h.Xname
+		}
+		waves := rollout.Plan(xnames, fwCanary, groupBy)
+
+		in := bufio.NewReader(cmd.InOrStdin())
+		attempted, failed := 0, 0
+		for i, w := range waves {
+			waveHosts := make([]bmcHost, 0, len(w.Members))
+			for _, m := range w.Members {
+				waveHosts = append(waveHosts, hosts[m])
+			}
+			if len(waves) > 1 {
+				if i > 0 && fwConfirmWaves && !fwDryRun {
+					fmt.Printf("Continue with wave %d/%d (%s, %d host(s))? [y/N] ", i+1, len(waves), w.Name, len(waveHosts))
+					answer, _ := in.ReadString('\n')
+					answer = strings.ToLower(strings.TrimSpace(answer))
+					if answer != "y" && answer != "yes" {
+						return fmt.Errorf("rollout stopped before wave %d/%d (%s)", i+1, len(waves), w.Name)
 					}
-					fmt.Println(dryRunMsg)
-					if cancel != nil {
-						cancel()
+				}
+				fmt.Printf("Wave %d/%d (%s): %d host(s)\n", i+1, len(waves), w.Name, len(waveHosts))
+			}
+
+			failedHosts := runFirmwareWave(cmd.Context(), waveHosts, user, pass, update, jw)
+			attempted += len(waveHosts)
+			failed += len(failedHosts)
+
+			// Before moving on, wait for the wave to report the expected version
+			if i < len(waves)-1 && (fwExpectedVersion != "" || update.Catalog != nil) && !fwDryRun {
+				pending := make([]bmcHost, 0, len(waveHosts))
+				for _, h := range waveHosts {
+					if !slices.Contains(failedHosts, h) {
+						pending = append(pending, h)
 					}
-					continue
 				}
-				err := redfish.SimpleUpdate(ctx, host, user, pass, fwInsecure, fwTimeout, fwImageURI, fwTargets, fwProtocol, fwExpectedVersion, fwForce)
-				if cancel != nil {
-					cancel()
+				notReady := waitForExpectedVersion(cmd.Context(), pending, user, pass, update)
+				for _, h := range notReady {
+					fmt.Fprintf(os.Stderr, "WARN: %s: did not reach version %s within %s\n", h.Host, update.forHost(h.Host).ExpectedVersion, fwWaveTimeout)
 				}
-				if err != nil {
-					// Check if this is a "skipping update" message
-					if strings.Contains(err.Error(), "skipping update") {
-						fmt.Printf("%s: %v\n", host, err)
-					} else {
-						fmt.Fprintf(os.Stderr, "WARN: %s: firmware update failed: %v\n", host, err)
-					}
-				} else {
-					fmt.Printf("Triggered firmware update on %s\n", host)
+				failed += len(notReady)
+			}
+
+			if threshold != nil && threshold.Exceeded(failed, attempted) {
+				return fmt.Errorf("halting rollout after wave %d/%d (%s): %d of %d host(s) failed (threshold %s)",
+					i+1, len(waves), w.Name, failed, attempted, threshold)
+			}
+		}
+		return nil
+	},
+}
+
+// preflightImage checks an image before it is handed to any BMC: reachability and size, the
+// expected digests, and the filename against --type. It runs for --image-uri when
+// --preflight or a digest flag is given, and for each image picked from --catalog with
+// --preflight.
+func preflightImage(ctx context.Context, uri, sha256, sha512 string) error {
+	res, err := fwimage.Check(ctx, fwimage.Options{
+		URI:      uri,
+		SHA256:   sha256,
+		SHA512:   sha512,
+		Type:     fwType,
+		Insecure: fwInsecure,
+		Timeout:  fwTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("Preflight: %s", uri)
+	if res.Size >= 0 {
+		msg += fmt.Sprintf(": %d bytes", res.Size)
+	}
+	for _, alg := range []string{"sha256", "sha512"} {
+		if _, ok := res.Digests[alg]; ok {
+			msg += fmt.Sprintf(", %s verified", alg)
+		}
+	}
+	fmt.Println(msg)
+	return nil
+}
+
+// fwUpdate describes one SimpleUpdate to post to a set of hosts.
+type fwUpdate struct {
+	ImageURI        string
+	Targets         []string
+	Protocol        string
+	ExpectedVersion string
+	Apply           redfish.ApplyTimeOptions
+	Policy          version.Policy
+	// Catalog holds the image picked from --catalog for each host, keyed by address; it
+	// overrides ImageURI and ExpectedVersion (see forHost)
+	Catalog map[string]catalogImage
+}
+
+// flagUpdate builds the update described by the firmware command flags.
+func flagUpdate(applyOpts redfish.ApplyTimeOptions) fwUpdate {
+	return fwUpdate{
+		ImageURI:        fwImageURI,
+		Targets:         fwTargets,
+		Protocol:        fwProtocol,
+		ExpectedVersion: fwExpectedVersion,
+		Apply:           applyOpts,
+	}
+}
+
+// runFirmwareWave posts SimpleUpdate to each host within the --batch-size and per-cabinet/chassis
+// limits, and returns the hosts whose update failed. Hosts skipped because they are already at
+// the expected version are not failures. Each host's start and outcome are recorded in jw when
+// it is not nil.
+func runFirmwareWave(parent context.Context, hosts []bmcHost, user, pass string, u fwUpdate, jw *journal.Writer) []bmcHost {
+	var mu sync.Mutex // Protect stdout/stderr writes and the failed list
+	var failed []bmcHost
+	power := fwPowerOptions()
+
+	update := func(h bmcHost) {
+		u := u.forHost(h.Host)
+		ctx := parent
+		if fwTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, fwTimeout)
+			defer cancel()
+		}
+		record := func(e journal.Entry) {
+			if jw == nil {
+				return
+			}
+			e.Host, e.Xname, e.Image, e.Targets = h.Host, h.Xname, u.ImageURI, u.Targets
+			if e.Event != journal.EventStart {
+				// parent, not ctx: verification can outlast the per-host timeout
+				e.Version = observedVersion(parent, h.Host, user, pass, u.Targets)
+			}
+			if err := jw.Record(e); err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: journal: %v\n", h.Host, err)
+			}
+		}
+
+		if fwDryRun {
+			dryRunMsg := fmt.Sprintf("[dry-run] would POST SimpleUpdate on %s with image=%s targets=%v protocol=%s",
+				h.Host, u.ImageURI, u.Targets, u.Protocol)
+			if u.ExpectedVersion != "" {
+				dryRunMsg += fmt.Sprintf(" expected-version=%s", u.ExpectedVersion)
+				if fwForce {
+					dryRunMsg += " (force=true)"
 				}
 			}
+			if u.Apply.ApplyTime != "" {
+				dryRunMsg += fmt.Sprintf(" apply-time=%s", u.Apply.ApplyTime)
+			}
+			if fwReboot != rebootNever {
+				dryRunMsg += fmt.Sprintf(" reboot=%s", fwReboot)
+			}
+			if fwRequireOff {
+				dryRunMsg += " (require-off)"
+			}
+			mu.Lock()
+			fmt.Println(dryRunMsg)
+			mu.Unlock()
+			return
+		}
+
+		record(journal.Entry{Event: journal.EventStart})
+		targets, err := resolveTargets(ctx, h.Host, user, pass, u.Targets)
+		if err != nil {
+			record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, Error: err.Error()})
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", h.Host, err)
+			failed = append(failed, h)
+			return
+		}
+		u.Targets = targets
+		if skip, err := versionGate(ctx, h.Host, user, pass, u); err != nil || skip != "" {
+			if err != nil {
+				record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, Error: err.Error()})
+			} else {
+				record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultSkipped})
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", h.Host, err)
+				failed = append(failed, h)
+			} else {
+				fmt.Printf("%s: skipping update: %s\n", h.Host, skip)
+			}
+			return
+		}
+
+		poweredOff, err := powerBeforeUpdate(parent, power, h.Host, user, pass)
+		if err != nil {
+			// Restore whatever was already shut down before giving up on the host
+			if len(poweredOff) > 0 {
+				if perr := powerAfterUpdate(parent, power, h.Host, user, pass, poweredOff); perr != nil {
+					err = fmt.Errorf("%w; %v", err, perr)
+				}
+			}
+			record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, Error: err.Error()})
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(os.Stderr, "WARN: %s: power: %v\n", h.Host, err)
+			failed = append(failed, h)
+			return
+		}
+
+		var before map[string]string
+		if fwVerify {
+			before = targetVersions(ctx, h.Host, user, pass, u.Targets)
+		}
+		taskURI, err := redfish.SimpleUpdateTask(ctx, h.Host, user, pass, fwInsecure, fwTimeout, u.ImageURI, u.Targets, u.Protocol, u.ExpectedVersion, fwForce, u.Apply)
+		err = pushURIHint(err)
+		switch {
+		case err == nil:
+			record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultTriggered, TaskURI: taskURI})
+		case strings.Contains(err.Error(), "skipping update"):
+			record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultSkipped})
+		default:
+			record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, TaskURI: taskURI, Error: err.Error()})
+		}
+
+		mu.Lock()
+		if err != nil {
+			// Check if this is a "skipping update" message
+			if strings.Contains(err.Error(), "skipping update") {
+				fmt.Printf("%s: %v\n", h.Host, err)
+			} else {
+				fmt.Fprintf(os.Stderr, "WARN: %s: firmware update failed: %v\n", h.Host, err)
+				failed = append(failed, h)
+			}
 		} else {
-			// Parallel execution with semaphore to limit concurrency
-			var wg sync.WaitGroup
-			sem := make(chan struct{}, fwBatchSize)
-			var mu sync.Mutex // Protect stdout/stderr writes
-
-			for _, host := range hosts {
-				wg.Add(1)
-				go func(h string) {
-					defer wg.Done()
-					sem <- struct{}{}        // Acquire semaphore
-					defer func() { <-sem }() // Release semaphore
-
-					ctx := cmd.Context()
-					var cancel context.CancelFunc
-					if fwTimeout > 0 {
-						ctx, cancel = context.WithTimeout(ctx, fwTimeout)
-					}
-					if cancel != nil {
-						defer cancel()
-					}
+			fmt.Printf("Triggered firmware update on %s\n", h.Host)
+		}
+		mu.Unlock()
+		if err != nil && len(poweredOff) > 0 {
+			// Nothing was staged, so only bring the nodes back
+			if perr := powerAfterUpdate(parent, power, h.Host, user, pass, poweredOff); perr != nil {
+				mu.Lock()
+				fmt.Fprintf(os.Stderr, "WARN: %s: power: %v\n", h.Host, perr)
+				mu.Unlock()
+			}
+		}
+		if err != nil {
+			return
+		}
+		if perr := powerAfterTask(parent, power, h.Host, user, pass, taskURI, poweredOff); perr != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", h.Host, perr)
+			failed = append(failed, h)
+			return
+		}
+		if !fwVerify {
+			return
+		}
 
-					if fwDryRun {
-						dryRunMsg := fmt.Sprintf("[dry-run] would POST SimpleUpdate on %s with image=%s targets=%v protocol=%s",
-							h, fwImageURI, fwTargets, fwProtocol)
-						if fwExpectedVersion != "" {
-							dryRunMsg += fmt.Sprintf(" expected-version=%s", fwExpectedVersion)
-							if fwForce {
-								dryRunMsg += " (force=true)"
-							}
-						}
-						mu.Lock()
-						fmt.Println(dryRunMsg)
-						mu.Unlock()
-						return
-					}
+		class, detail := verifyUpdate(parent, h.Host, user, pass, u, before, fwVerifyTimeout, fwVerifyInterval)
+		record(journal.Entry{Event: journal.EventVerify, Result: class, TaskURI: taskURI})
+		mu.Lock()
+		defer mu.Unlock()
+		if class == journal.ResultUpdated {
+			fmt.Printf("Verified %s: %s (%s)\n", h.Host, class, detail)
+		} else {
+			fmt.Fprintf(os.Stderr, "WARN: %s: verification: %s (%s)\n", h.Host, class, detail)
+			failed = append(failed, h)
+		}
+	}
 
-					err := redfish.SimpleUpdate(ctx, h, user, pass, fwInsecure, fwTimeout, fwImageURI, fwTargets, fwProtocol, fwExpectedVersion, fwForce)
-
-					mu.Lock()
-					if err != nil {
-						// Check if this is a "skipping update" message
-						if strings.Contains(err.Error(), "skipping update") {
-							fmt.Printf("%s: %v\n", h, err)
-						} else {
-							fmt.Fprintf(os.Stderr, "WARN: %s: firmware update failed: %v\n", h, err)
-						}
-					} else {
-						fmt.Printf("Triggered firmware update on %s\n", h)
-					}
-					mu.Unlock()
-				}(host)
+	runHosts(hosts, fwLimits(), func(_ int, h bmcHost) { update(h) })
+	return failed
+}
+
+// versionGate reads the current version of each target of u on host and decides whether the
+// update should go ahead. It returns a skip reason when the host needs no update (already at
+// the expected version, or at or above --min-version) and an error when the update would be
+// a downgrade without --allow-downgrade. Targets whose version cannot be read or parsed do not
+// block the update, and a target that cannot be read means the host is never skipped.
+func versionGate(ctx context.Context, host, user, pass string, u fwUpdate) (string, error) {
+	pol := u.Policy
+	if u.ExpectedVersion == "" && pol.Min == nil {
+		return "", nil
+	}
+	p := versionParser()
+	current := make([]string, 0, len(u.Targets))
+	unread := false
+	for _, target := range u.Targets {
+		inv, err := redfish.GetFirmwareInventory(ctx, host, user, pass, fwInsecure, fwTimeout, target)
+		if err != nil {
+			unread = true
+			continue
+		}
+		if u.ExpectedVersion != "" && !fwAllowDowngrade {
+			if c, ok := version.CompareStrings(p, inv.Version, u.ExpectedVersion); ok && c > 0 {
+				return "", fmt.Errorf("refusing downgrade of %s from %s to %s (use --allow-downgrade)", target, inv.Version, u.ExpectedVersion)
 			}
-			wg.Wait()
 		}
-		return nil
-	},
+		current = append(current, inv.Version)
+	}
+	if fwForce || unread {
+		return "", nil
+	}
+	allExpected, allAboveMin := u.ExpectedVersion != "", pol.Min != nil
+	for _, cur := range current {
+		if allExpected && !version.Equal(p, cur, u.ExpectedVersion) {
+			allExpected = false
+		}
+		if allAboveMin {
+			if v, err := p.Parse(cur); err != nil || version.Compare(v, *pol.Min) < 0 {
+				allAboveMin = false
+			}
+		}
+	}
+	switch {
+	case allExpected:
+		return fmt.Sprintf("all targets already at expected version %s", u.ExpectedVersion), nil
+	case allAboveMin:
+		return fmt.Sprintf("all targets already at or above minimum version %s", pol.Min), nil
+	}
+	return "", nil
+}
+
+// waitForExpectedVersion polls the targets of each host every --wave-interval until they all
+// report the expected version of u or --wave-timeout passes. It returns the hosts that never did.
+func waitForExpectedVersion(parent context.Context, hosts []bmcHost, user, pass string, u fwUpdate) []bmcHost {
+	ctx := parent
+	if fwWaveTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, fwWaveTimeout)
+		defer cancel()
+	}
+	pending := hosts
+	for len(pending) > 0 {
+		want := u.ExpectedVersion
+		if u.Catalog != nil {
+			want = "from the catalog"
+		}
+		fmt.Printf("Waiting for %d host(s) to report version %s\n", len(pending), want)
+		var next []bmcHost
+		for _, h := range pending {
+			if hu := u.forHost(h.Host); !atVersion(ctx, h.Host, user, pass, hu.Targets, hu.ExpectedVersion) {
+				next = append(next, h)
+			}
+		}
+		pending = next
+		if len(pending) == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return pending
+		case <-time.After(fwWaveInterval):
+		}
+	}
+	return nil
+}
+
+// atVersion reports whether every firmware target on host reports the wanted version under
+// --version-scheme.
+func atVersion(ctx context.Context, host, user, pass string, targets []string, want string) bool {
+	for _, target := range targets {
+		inv, err := redfish.GetFirmwareInventory(ctx, host, user, pass, fwInsecure, fwTimeout, target)
+		if err != nil || !version.Equal(versionParser(), inv.Version, want) {
+			return false
+		}
+	}
+	return true
 }
 
 func init() {
@@ -223,4 +675,25 @@ func init() {
 	firmwareCmd.PersistentFlags().BoolVar(&fwForce, "force", false, "force update even if already at expected version")
 	firmwareCmd.PersistentFlags().StringVar(&fwExpectedVersion, "expected-version", "", "expected version string; skip update if already at this version (unless --force)")
 	firmwareCmd.PersistentFlags().IntVar(&fwBatchSize, "batch-size", 0, "number of concurrent firmware updates (0 or 1 = serial, >1 = parallel)")
+	firmwareCmd.PersistentFlags().IntVar(&fwMaxPerCabinet, "max-per-cabinet", 0, "maximum concurrent BMCs per cabinet, from bmcs[] xnames (0 = no limit)")
+	firmwareCmd.PersistentFlags().IntVar(&fwMaxPerChassis, "max-per-chassis", 0, "maximum concurrent BMCs per chassis, from bmcs[] xnames (0 = no limit)")
+	firmwareCmd.PersistentFlags().StringVar(&fwApplyTime, "apply-time", "", "when the BMC applies the image: Immediate|OnReset|AtMaintenanceWindowStart|InMaintenanceWindowOnReset")
+	firmwareCmd.PersistentFlags().StringVar(&fwWindowStart, "maintenance-window-start", "", "maintenance window start time (RFC3339), used with a maintenance-window --apply-time")
+	firmwareCmd.PersistentFlags().DurationVar(&fwWindowDuration, "maintenance-window-duration", 0, "maintenance window duration, used with a maintenance-window --apply-time")
+	firmwareCmd.PersistentFlags().BoolVar(&fwPushURIApply, "set-push-uri-apply-time", false, "allow --apply-time to change the BMC-wide UpdateService HttpPushUriOptions when SimpleUpdate has no per-request apply time; the setting is not restored afterwards")
+	firmwareCmd.PersistentFlags().StringVar(&fwVersionScheme, "version-scheme", "exact", "how versions are compared: exact (same string; ordered by the first numeric run for policies and downgrades), dotted (first numeric run, e.g. nc.1.5.2), semver, or regex:<pattern> with numeric capture groups")
+	firmwareCmd.PersistentFlags().StringVar(&fwMinVersion, "min-version", "", "minimum acceptable version; hosts already at or above it are skipped, and status flags hosts below it")
+	firmwareCmd.PersistentFlags().StringVar(&fwMaxVersion, "max-version", "", "maximum acceptable version; --expected-version may not exceed it, and status flags hosts above it")
+	firmwareCmd.PersistentFlags().BoolVar(&fwAllowDowngrade, "allow-downgrade", false, "allow updating targets that report a newer version than --expected-version")
+	firmwareCmd.Flags().BoolVar(&fwPreflight, "preflight", false, "check that --image-uri is reachable, non-empty and named for --type before contacting any BMC")
+	firmwareCmd.Flags().StringVar(&fwSHA256, "sha256", "", "expected SHA-256 of the image; downloads and verifies it before contacting any BMC (implies --preflight)")
+	firmwareCmd.Flags().StringVar(&fwSHA512, "sha512", "", "expected SHA-512 of the image; downloads and verifies it before contacting any BMC (implies --preflight)")
+	firmwareCmd.PersistentFlags().StringVar(&fwJournal, "journal", "", "append a JSON lines record of each host's update start and outcome to this file")
+	firmwareCmd.Flags().BoolVar(&fwResume, "resume", false, "skip hosts the --journal records as completed and re-check hosts left in flight")
+	firmwareCmd.Flags().IntVar(&fwCanary, "canary", 0, "number of hosts to update first as a canary wave")
+	firmwareCmd.Flags().StringVar(&fwWaveBy, "wave-by", "none", "group hosts after the canary into waves by xname prefix: none|cabinet|chassis")
+	firmwareCmd.Flags().StringVar(&fwFailureThreshold, "failure-threshold", "", "halt later waves when failures exceed this host count or percentage (e.g. 2 or 10%)")
+	firmwareCmd.Flags().DurationVar(&fwWaveTimeout, "wave-timeout", 30*time.Minute, "maximum time to wait for a wave to reach --expected-version before the next wave")
+	firmwareCmd.Flags().DurationVar(&fwWaveInterval, "wave-interval", 30*time.Second, "poll interval while waiting for a wave to reach --expected-version")
+	firmwareCmd.Flags().BoolVar(&fwConfirmWaves, "confirm-waves", false, "prompt for confirmation before starting each wave after the first")
 }
@@ -0,0 +1,327 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/journal"
+	"bootstrap/internal/redfish"
+	"bootstrap/internal/updateplan"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	aplPlan           string
+	aplImageBaseURL   string
+	aplVerifyTimeout  time.Duration
+	aplVerifyInterval time.Duration
+	aplPowerTimeout   time.Duration
+	aplPowerInterval  time.Duration
+	aplTaskTimeout    time.Duration
+)
+
+// Per-host step results of `firmware apply`.
+const (
+	stepUpdated = "updated"
+	stepSkipped = "skipped" // already at the step's version
+	stepNA      = "n/a"     // the step's hosts patterns do not match the host
+	stepFailed  = "failed"
+	stepBlocked = "blocked" // an earlier step failed on the host
+)
+
+// planStep is a plan step with its targets, image and power handling resolved.
+type planStep struct {
+	updateplan.Step
+	update fwUpdate
+	power  powerOptions
+}
+
+// stepOutcome is the result of one step on one host.
+type stepOutcome struct {
+	Step   string
+	Result string
+	Detail string
+}
+
+var firmwareApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a multi-step firmware update plan, host by host in dependency order",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if aplPlan == "" {
+			return errors.New("--plan is required")
+		}
+		doc, err := updateplan.Load(aplPlan)
+		if err != nil {
+			return err
+		}
+		ordered, err := doc.Order()
+		if err != nil {
+			return err
+		}
+		applyOpts, err := applyTimeOptions()
+		if err != nil {
+			return err
+		}
+		if applyOpts.ApplyTime != "" && applyOpts.ApplyTime != redfish.ApplyImmediate {
+			return fmt.Errorf("--apply-time %s cannot be used with apply: each step is verified before the next one starts", applyOpts.ApplyTime)
+		}
+		pol, err := versionPolicy()
+		if err != nil {
+			return err
+		}
+		steps := make([]planStep, 0, len(ordered))
+		for _, s := range ordered {
+			targets := s.Targets
+			if len(targets) == 0 {
+				if targets, err = defaultTargets(s.Type); err != nil {
+					return fmt.Errorf("%s: %w", s.Name, err)
+				}
+			}
+			image, err := s.Image(aplImageBaseURL)
+			if err != nil {
+				return err
+			}
+			protocol := s.Protocol
+			if protocol == "" {
+				protocol = fwProtocol
+			}
+			if v := pol.Check(s.Version); v != "" {
+				return fmt.Errorf("%s: version %s violates the version policy (%s)", s.Name, s.Version, v)
+			}
+			reboot := s.Reboot
+			if reboot == "" {
+				reboot = rebootNever
+			}
+			steps = append(steps, planStep{
+				Step: s,
+				update: fwUpdate{
+					ImageURI:        image,
+					Targets:         targets,
+					Protocol:        protocol,
+					ExpectedVersion: s.Version,
+					Apply:           applyOpts,
+					Policy:          pol,
+				},
+				power: powerOptions{
+					Reboot:      reboot,
+					Timeout:     aplPowerTimeout,
+					Interval:    aplPowerInterval,
+					TaskTimeout: aplTaskTimeout,
+				},
+			})
+		}
+
+		user := os.Getenv("REDFISH_USER")
+		pass := os.Getenv("REDFISH_PASSWORD")
+		if user == "" || pass == "" {
+			return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
+		}
+		hosts, err := loadBMCHosts(fwFile, fwHostsCSV)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, len(steps))
+		for i, s := range steps {
+			names[i] = s.Name
+		}
+		fmt.Printf("Plan order: %s\n", strings.Join(names, " -> "))
+
+		if fwDryRun {
+			for _, h := range hosts {
+				var todo []string
+				for _, s := range steps {
+					switch {
+					case !s.Matches(h.Xname, h.Host):
+					case s.power.Reboot != rebootNever:
+						todo = append(todo, fmt.Sprintf("%s (%s, reboot %s)", s.Name, s.Version, s.power.Reboot))
+					default:
+						todo = append(todo, fmt.Sprintf("%s (%s)", s.Name, s.Version))
+					}
+				}
+				fmt.Printf("[dry-run] %s: would apply %s\n", h.label(), strings.Join(todo, ", then "))
+			}
+			return nil
+		}
+
+		jw, err := openJournal()
+		if err != nil {
+			return err
+		}
+		if jw != nil {
+			defer jw.Close() //nolint:errcheck
+		}
+
+		var mu sync.Mutex // Serializes progress output
+		outcomes := make([][]stepOutcome, len(hosts))
+		runHosts(hosts, fwLimits(), func(i int, h bmcHost) {
+			outcomes[i] = applySteps(cmd.Context(), h, user, pass, steps, jw, &mu)
+		})
+		if failed := printApplySummary(hosts, outcomes); failed > 0 {
+			return fmt.Errorf("%d host(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+// applySteps runs the plan on one host. Each step is gated on the host's current version,
+// posted, followed by its reboot handling, and verified to run its version before the next
+// step starts; after a failure the remaining steps are blocked on this host only.
+func applySteps(parent context.Context, h bmcHost, user, pass string, steps []planStep, jw *journal.Writer, mu *sync.Mutex) []stepOutcome {
+	report := func(o stepOutcome) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case o.Result == stepFailed:
+			fmt.Fprintf(os.Stderr, "WARN: %s: step %s failed: %s\n", h.label(), o.Step, o.Detail)
+		case o.Detail != "":
+			fmt.Printf("%s: step %s %s (%s)\n", h.label(), o.Step, o.Result, o.Detail)
+		default:
+			fmt.Printf("%s: step %s %s\n", h.label(), o.Step, o.Result)
+		}
+	}
+	out := make([]stepOutcome, 0, len(steps))
+	failedStep := ""
+	for _, s := range steps {
+		o := stepOutcome{Step: s.Name}
+		switch {
+		case !s.Matches(h.Xname, h.Host):
+			o.Result = stepNA
+		case failedStep != "":
+			o.Result, o.Detail = stepBlocked, "after failed step "+failedStep
+		default:
+			o.Result, o.Detail = applyStep(parent, h, user, pass, s.update, s.power, jw)
+			report(o)
+			if o.Result == stepFailed {
+				failedStep = s.Name
+			}
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+// applyStep updates one host for one step and returns the step result and a detail.
+func applyStep(parent context.Context, h bmcHost, user, pass string, u fwUpdate, power powerOptions, jw *journal.Writer) (string, string) {
+	ctx := parent
+	if fwTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, fwTimeout)
+		defer cancel()
+	}
+	record := func(e journal.Entry) {
+		if jw == nil {
+			return
+		}
+		e.Host, e.Xname, e.Image, e.Targets = h.Host, h.Xname, u.ImageURI, u.Targets
+		if e.Event != journal.EventStart {
+			e.Version = observedVersion(parent, h.Host, user, pass, u.Targets)
+		}
+		if err := jw.Record(e); err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s: journal: %v\n", h.Host, err)
+		}
+	}
+
+	record(journal.Entry{Event: journal.EventStart})
+	targets, err := resolveTargets(ctx, h.Host, user, pass, u.Targets)
+	if err != nil {
+		record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, Error: err.Error()})
+		return stepFailed, err.Error()
+	}
+	u.Targets = targets
+	skip, err := versionGate(ctx, h.Host, user, pass, u)
+	switch {
+	case err != nil:
+		record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, Error: err.Error()})
+		return stepFailed, err.Error()
+	case skip != "":
+		record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultSkipped})
+		return stepSkipped, skip
+	}
+
+	before := targetVersions(ctx, h.Host, user, pass, u.Targets)
+	poweredOff, err := powerBeforeUpdate(parent, power, h.Host, user, pass)
+	if err != nil {
+		// Restore whatever was already shut down before giving up on the step
+		if len(poweredOff) > 0 {
+			if perr := powerAfterUpdate(parent, power, h.Host, user, pass, poweredOff); perr != nil {
+				err = fmt.Errorf("%w; %v", err, perr)
+			}
+		}
+		record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, Error: err.Error()})
+		return stepFailed, "power: " + err.Error()
+	}
+	taskURI, err := redfish.SimpleUpdateTask(ctx, h.Host, user, pass, fwInsecure, fwTimeout, u.ImageURI, u.Targets, u.Protocol, u.ExpectedVersion, fwForce, u.Apply)
+	err = pushURIHint(err)
+	if err != nil && len(poweredOff) > 0 {
+		// Nothing was staged, so only bring the nodes back
+		if perr := powerAfterUpdate(parent, power, h.Host, user, pass, poweredOff); perr != nil {
+			err = fmt.Errorf("%w; power: %v", err, perr)
+		}
+	}
+	switch {
+	case err != nil && strings.Contains(err.Error(), "skipping update"):
+		record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultSkipped})
+		return stepSkipped, err.Error()
+	case err != nil:
+		record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultFailed, TaskURI: taskURI, Error: err.Error()})
+		return stepFailed, err.Error()
+	}
+	record(journal.Entry{Event: journal.EventFinish, Result: journal.ResultTriggered, TaskURI: taskURI})
+	if err := powerAfterTask(parent, power, h.Host, user, pass, taskURI, poweredOff); err != nil {
+		return stepFailed, err.Error()
+	}
+
+	class, detail := verifyUpdate(parent, h.Host, user, pass, u, before, aplVerifyTimeout, aplVerifyInterval)
+	record(journal.Entry{Event: journal.EventVerify, Result: class, TaskURI: taskURI})
+	if class != journal.ResultUpdated {
+		return stepFailed, fmt.Sprintf("verification: %s: %s", class, detail)
+	}
+	return stepUpdated, detail
+}
+
+// printApplySummary prints one row per host and step and returns the number of hosts with
+// a failed step.
+func printApplySummary(hosts []bmcHost, outcomes [][]stepOutcome) int {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tSTEP\tRESULT\tDETAIL") //nolint:errcheck
+	counts := map[string]int{}
+	failedHosts := 0
+	for i, h := range hosts {
+		failed := false
+		for _, o := range outcomes[i] {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", h.label(), o.Step, o.Result, o.Detail) //nolint:errcheck
+			counts[o.Result]++
+			failed = failed || o.Result == stepFailed
+		}
+		if failed {
+			failedHosts++
+		}
+	}
+	tw.Flush() //nolint:errcheck
+	fmt.Printf("Steps: %d updated, %d skipped, %d failed, %d blocked, %d n/a\n",
+		counts[stepUpdated], counts[stepSkipped], counts[stepFailed], counts[stepBlocked], counts[stepNA])
+	return failedHosts
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareApplyCmd)
+	firmwareApplyCmd.Flags().StringVar(&aplPlan, "plan", "", "update plan YAML listing steps with targets, images, versions and depends_on (required)")
+	firmwareApplyCmd.Flags().StringVar(&aplImageBaseURL, "image-base-url", "", "base URL that image_file entries in the plan are resolved against")
+	firmwareApplyCmd.Flags().DurationVar(&aplVerifyTimeout, "verify-timeout", 20*time.Minute, "maximum time to wait per host and step for the new version, including BMC reboots")
+	firmwareApplyCmd.Flags().DurationVar(&aplVerifyInterval, "verify-interval", 15*time.Second, "poll interval while verifying a step")
+	firmwareApplyCmd.Flags().DurationVar(&aplPowerTimeout, "power-timeout", 10*time.Minute, "maximum time to wait for a node to power off, or to power on and boot, in steps with reboot")
+	firmwareApplyCmd.Flags().DurationVar(&aplPowerInterval, "power-interval", 10*time.Second, "poll interval while waiting for the update task and node power state in steps with reboot")
+	firmwareApplyCmd.Flags().DurationVar(&aplTaskTimeout, "task-timeout", 30*time.Minute, "maximum time to wait for a step's update task to finish before its reboot touches node power")
+}
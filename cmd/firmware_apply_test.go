@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// planTestServer serves FirmwareInventory targets at version 1.0. A SimpleUpdate moves its
+// targets to 2.0 unless the target is listed in stuck, and starts task 1, which is Running on
+// its first poll and Completed after. The targets of each update and the type of each node
+// reset are recorded in order; a reset while the task is Running fails the test.
+func planTestServer(t *testing.T, stuck []string, posted *[]string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	versions := map[string]string{}
+	taskState := ""
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/redfish/v1/Systems":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"}]}`))
+		case r.URL.Path == "/redfish/v1/Systems/Node0":
+			_, _ = w.Write([]byte(`{"Id":"Node0","PowerState":"On"}`))
+		case r.URL.Path == "/redfish/v1/TaskService/Tasks/1":
+			json.NewEncoder(w).Encode(map[string]any{"TaskState": taskState}) //nolint:errcheck
+			taskState = "Completed"
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/ComputerSystem.Reset"):
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if taskState == "Running" {
+				t.Errorf("%s reset while the update task is still Running", body["ResetType"])
+			}
+			*posted = append(*posted, body["ResetType"])
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST":
+			var body struct{ Targets []string }
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			for _, target := range body.Targets {
+				*posted = append(*posted, filepath.Base(target))
+				if !slices.Contains(stuck, filepath.Base(target)) {
+					versions[target] = "2.0"
+				}
+			}
+			taskState = "Running"
+			w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/1")
+			w.WriteHeader(http.StatusAccepted)
+		case strings.Contains(r.URL.Path, "/UpdateService/FirmwareInventory/"):
+			v := versions[r.URL.Path]
+			if v == "" {
+				v = "1.0"
+			}
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Version": v,
+				"Status":  map[string]any{"State": "Enabled", "Health": "OK"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFirmwareApplyPlan(t *testing.T) {
+	var goodPosts, stuckPosts []string
+	good := planTestServer(t, nil, &goodPosts)
+	stuck := planTestServer(t, []string{"BMC"}, &stuckPosts)
+	configureRollout(t, good)
+	goodHost := strings.TrimPrefix(good.URL, "https://")
+	stuckHost := strings.TrimPrefix(stuck.URL, "https://")
+	fwHostsCSV = goodHost + "," + stuckHost
+	fwBatchSize = 2
+	aplVerifyTimeout, aplVerifyInterval = 300*time.Millisecond, 10*time.Millisecond
+
+	aplPlan = filepath.Join(t.TempDir(), "plan.yaml")
+	t.Cleanup(func() { aplPlan = "" })
+	if err := os.WriteFile(aplPlan, []byte(`steps:
+  - name: nic
+    targets: [/redfish/v1/UpdateService/FirmwareInventory/NIC0]
+    version: "2.0"
+    image_uri: http://10.0.0.1/nic.bin
+    depends_on: [bios]
+  - name: bios
+    targets: [/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS]
+    version: "2.0"
+    image_uri: http://10.0.0.1/bios.bin
+    depends_on: [bmc]
+  - name: bmc
+    type: bmc
+    version: "2.0"
+    image_uri: http://10.0.0.1/bmc.bin
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := runCmd(t, firmwareApplyCmd)
+	if err == nil || err.Error() != "1 host(s) failed" {
+		t.Fatalf("expected one failed host, got %v\nOutput: %s", err, output)
+	}
+	if want := []string{"BMC", "Node0.BIOS", "NIC0"}; !slices.Equal(goodPosts, want) {
+		t.Errorf("good host updates = %v, want %v", goodPosts, want)
+	}
+	// The BMC step never verifies, so BIOS and NIC are not attempted on that host
+	if want := []string{"BMC"}; !slices.Equal(stuckPosts, want) {
+		t.Errorf("stuck host updates = %v, want %v", stuckPosts, want)
+	}
+	for _, want := range []string{
+		"Plan order: bmc -> bios -> nic",
+		"WARN: " + stuckHost + ": step bmc failed: verification: unchanged",
+		"Steps: 3 updated, 0 skipped, 1 failed, 2 blocked, 0 n/a",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestFirmwareApplyStepReboot(t *testing.T) {
+	var posts []string
+	server := planTestServer(t, nil, &posts)
+	configureRollout(t, server)
+	fwHostsCSV = strings.TrimPrefix(server.URL, "https://")
+	aplVerifyTimeout, aplVerifyInterval = 300*time.Millisecond, 10*time.Millisecond
+	aplPowerTimeout, aplPowerInterval, aplTaskTimeout = 300*time.Millisecond, 10*time.Millisecond, 300*time.Millisecond
+
+	aplPlan = filepath.Join(t.TempDir(), "plan.yaml")
+	t.Cleanup(func() { aplPlan = "" })
+	if err := os.WriteFile(aplPlan, []byte(`steps:
+  - name: bios
+    targets: [/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS]
+    version: "2.0"
+    image_uri: http://10.0.0.1/bios.bin
+    reboot: after
+  - name: nic
+    targets: [/redfish/v1/UpdateService/FirmwareInventory/NIC0]
+    version: "2.0"
+    image_uri: http://10.0.0.1/nic.bin
+    depends_on: [bios]
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := runCmd(t, firmwareApplyCmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+	}
+	// The node restarts once the BIOS task finishes, and before the NIC step starts
+	if want := []string{"Node0.BIOS", "GracefulRestart", "NIC0"}; !slices.Equal(posts, want) {
+		t.Errorf("requests = %v, want %v", posts, want)
+	}
+	if !strings.Contains(output, "Steps: 2 updated, 0 skipped, 0 failed, 0 blocked, 0 n/a") {
+		t.Errorf("unexpected summary:\n%s", output)
+	}
+}
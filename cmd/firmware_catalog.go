@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"bootstrap/internal/catalog"
+	"bootstrap/internal/redfish"
+	"bootstrap/internal/version"
+)
+
+var (
+	fwCatalog        string
+	fwCatalogBaseURL string
+)
+
+// catalogImage is the image picked from --catalog for one host.
+type catalogImage struct {
+	URI     string
+	Version string
+	SHA256  string
+}
+
+// forHost returns u with the image and expected version picked from --catalog for host, if any.
+func (u fwUpdate) forHost(host string) fwUpdate {
+	if img, ok := u.Catalog[host]; ok {
+		u.ImageURI, u.ExpectedVersion = img.URI, img.Version
+	}
+	return u
+}
+
+// resolveCatalog picks the --catalog image of type --type for each host, based on the
+// Manufacturer and Model of the BMC's Manager and Chassis. Only images matching
+// --expected-version (when set) and the version policy are considered. Hosts without a
+// compatible image, or whose hardware cannot be read, are reported and left out of the
+// returned list.
+func resolveCatalog(parent context.Context, hosts []bmcHost, user, pass string, pol version.Policy) ([]bmcHost, map[string]catalogImage, error) {
+	cat, err := catalog.Load(fwCatalog)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := versionParser()
+	accept := func(img catalog.Image) bool {
+		if fwExpectedVersion != "" && !version.Equal(p, img.Version, fwExpectedVersion) {
+			return false
+		}
+		return pol.Check(img.Version) == ""
+	}
+
+	type result struct {
+		img   catalog.Image
+		uri   string
+		hw    []catalog.Product
+		err   error
+		found bool
+	}
+	results := make([]result, len(hosts))
+	runHosts(hosts, fwLimits(), func(i int, h bmcHost) {
+		ctx := parent
+		if fwTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, fwTimeout)
+			defer cancel()
+		}
+		products, err := redfish.GetProducts(ctx, h.Host, user, pass, fwInsecure, fwTimeout)
+		if err != nil {
+			results[i].err = fmt.Errorf("read Manager/Chassis model: %w", err)
+			return
+		}
+		for _, prod := range products {
+			results[i].hw = append(results[i].hw, catalog.Product{Manufacturer: prod.Manufacturer, Model: prod.Model})
+		}
+		img, err := cat.Select(fwType, results[i].hw, p, accept)
+		if err == nil {
+			results[i].uri, err = img.Location(fwCatalogBaseURL)
+		}
+		results[i].img, results[i].err, results[i].found = img, err, err == nil
+	})
+
+	order := make([]int, len(hosts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return hosts[order[a]].label() < hosts[order[b]].label() })
+
+	images := map[string]catalogImage{}
+	var matched []bmcHost
+	var missing int
+	for _, i := range order {
+		h, r := hosts[i], results[i]
+		if !r.found {
+			if errors.Is(r.err, catalog.ErrNoCompatible) {
+				missing++
+			}
+			fmt.Fprintf(os.Stderr, "WARN: %s: not updated: %v\n", h.label(), r.err)
+			continue
+		}
+		fmt.Printf("%s: catalog image %s (%s)\n", h.label(), r.img.Label(), r.img.Version)
+		images[h.Host] = catalogImage{URI: r.uri, Version: r.img.Version, SHA256: r.img.SHA256}
+	}
+	// Keep the inventory order for the rollout itself
+	for _, h := range hosts {
+		if _, ok := images[h.Host]; ok {
+			matched = append(matched, h)
+		}
+	}
+	if missing > 0 {
+		fmt.Printf("No compatible catalog image: %d host(s)\n", missing)
+	}
+	return matched, images, nil
+}
+
+// preflightCatalog runs the image preflight on each distinct image picked from the catalog,
+// checking the catalog's sha256 where one is given.
+func preflightCatalog(ctx context.Context, images map[string]catalogImage) error {
+	if !fwPreflight {
+		return nil
+	}
+	sums := map[string]string{}
+	for _, img := range images {
+		if _, ok := sums[img.URI]; !ok || img.SHA256 != "" {
+			sums[img.URI] = img.SHA256
+		}
+	}
+	uris := make([]string, 0, len(sums))
+	for uri := range sums {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		if err := preflightImage(ctx, uri, sums[uri], ""); err != nil {
+			return fmt.Errorf("%s: %w", uri, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	firmwareCmd.Flags().StringVar(&fwCatalog, "catalog", "", "firmware catalog YAML file, or directory of them, to pick each host's --type image from by Manager/Chassis Manufacturer and Model (instead of --image-uri)")
+	firmwareCmd.Flags().StringVar(&fwCatalogBaseURL, "catalog-base-url", "", "base URL that catalog path entries are resolved against")
+}
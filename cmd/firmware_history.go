@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/history"
+	"bootstrap/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fwHistory   string
+	histHost    string
+	histVersion string
+	histSince   string
+	histUntil   string
+	histSamples bool
+)
+
+// histTimeZone is the zone history times are printed and parsed in.
+var histTimeZone = time.Local
+
+// recordHistory appends the summaries of one status run to --history.
+func recordHistory(summaries []hostSummary) error {
+	store, err := history.Open(fwHistory)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	samples := make([]history.Sample, 0, len(summaries))
+	for _, s := range summaries {
+		samples = append(samples, history.Sample{
+			Time:      now,
+			Host:      s.Host,
+			Xname:     s.Xname,
+			Target:    s.Target,
+			Version:   s.ObservedVersion,
+			Requested: s.RequestedVersion,
+			Status:    s.Status,
+			Policy:    s.Policy,
+			Error:     s.Error,
+		})
+	}
+	if err := store.Append(samples); err != nil {
+		store.Close() //nolint:errcheck
+		return err
+	}
+	return store.Close()
+}
+
+// parseHistoryTime accepts an RFC3339 time, a date (2006-01-02, local time) or a duration
+// meaning that long before now (e.g. 72h).
+func parseHistoryTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, histTimeZone); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want RFC3339, YYYY-MM-DD or a duration such as 72h)", s)
+}
+
+// sampleCompliant reports whether a sample counts as compliant: at --version when given,
+// otherwise at the run's requested version (if any) and inside the run's version policy.
+// Targets in error never count.
+func sampleCompliant(p version.Parser) func(history.Sample) bool {
+	return func(s history.Sample) bool {
+		if s.Status == "error" {
+			return false
+		}
+		if histVersion != "" {
+			return version.Equal(p, s.Version, histVersion)
+		}
+		return s.Policy == "" && (s.Requested == "" || version.Equal(p, s.Version, s.Requested))
+	}
+}
+
+var firmwareHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Report firmware compliance over time from status runs recorded with --history",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if fwHistory == "" {
+			return errors.New("--history is required")
+		}
+		now := time.Now()
+		since, err := parseHistoryTime(histSince, now)
+		if err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+		until, err := parseHistoryTime(histUntil, now)
+		if err != nil {
+			return fmt.Errorf("--until: %w", err)
+		}
+		if _, err := os.Stat(fwHistory); err != nil {
+			return fmt.Errorf("--history: %w", err)
+		}
+		store, err := history.Open(fwHistory)
+		if err != nil {
+			return err
+		}
+		defer store.Close() //nolint:errcheck
+
+		samples, err := store.Samples(history.Query{Host: histHost, Since: since, Until: until})
+		if err != nil {
+			return err
+		}
+		if len(samples) == 0 {
+			fmt.Println("No samples in range")
+			return nil
+		}
+
+		if histSamples {
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "TIME\tNAME\tTARGET\tVERSION\tSTATUS\tERROR") //nolint:errcheck
+			for _, s := range samples {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Time.In(histTimeZone).Format(time.RFC3339), s.Label(), s.Target, s.Version, s.Status, s.Error) //nolint:errcheck
+			}
+			tw.Flush() //nolint:errcheck
+			fmt.Println()
+		}
+
+		points := history.Compliance(samples, sampleCompliant(versionParser()))
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIME\tTARGETS\tCOMPLIANT\tPERCENT") //nolint:errcheck
+		var reached time.Time
+		for _, p := range points {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\n", p.Time.In(histTimeZone).Format(time.RFC3339), p.Total, p.Compliant, p.Percent()) //nolint:errcheck
+			switch {
+			case p.Compliant < p.Total:
+				reached = time.Time{}
+			case reached.IsZero():
+				reached = p.Time
+			}
+		}
+		tw.Flush() //nolint:errcheck
+		if !reached.IsZero() {
+			fmt.Printf("Fully compliant since %s\n", reached.In(histTimeZone).Format(time.RFC3339))
+		} else {
+			fmt.Println("Not fully compliant at the last run")
+		}
+
+		if changes := history.StatusChanges(samples); len(changes) > 0 {
+			fmt.Printf("Status changes: %d\n", len(changes))
+			for _, c := range changes {
+				fmt.Printf("  %s %s %s: %s -> %s\n", c.Time.In(histTimeZone).Format(time.RFC3339), c.Label, c.Target, c.From, c.To)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareHistoryCmd)
+	firmwareStatusCmd.Flags().StringVar(&fwHistory, "history", "", "append this run's results to a local history file for `firmware history`")
+	firmwareHistoryCmd.Flags().StringVar(&fwHistory, "history", "", "history file written by `firmware status --history` (required)")
+	firmwareHistoryCmd.Flags().StringVar(&histHost, "host", "", "only hosts whose xname or address matches this glob pattern (e.g. x9000c3*)")
+	firmwareHistoryCmd.Flags().StringVar(&histVersion, "version", "", "count targets at this version as compliant (compared using --version-scheme) instead of each run's --expected-version and policy")
+	firmwareHistoryCmd.Flags().StringVar(&histSince, "since", "", "start of the time range: RFC3339 time, YYYY-MM-DD, or a duration ago such as 72h")
+	firmwareHistoryCmd.Flags().StringVar(&histUntil, "until", "", "end of the time range, in the same forms as --since")
+	firmwareHistoryCmd.Flags().BoolVar(&histSamples, "samples", false, "also list every recorded sample")
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"bootstrap/internal/journal"
+	"bootstrap/internal/redfish"
+)
+
+// openJournal opens --journal for appending. It returns nil when no journal was requested
+// or for dry runs, which do not touch any BMC.
+func openJournal() (*journal.Writer, error) {
+	if fwJournal == "" || fwDryRun {
+		return nil, nil
+	}
+	jw, err := journal.Open(fwJournal)
+	if err != nil {
+		return nil, fmt.Errorf("--journal: %w", err)
+	}
+	return jw, nil
+}
+
+// readJournal summarizes --journal for image, warning about unreadable lines.
+func readJournal(image string) ([]journal.HostState, error) {
+	entries, skipped, err := journal.Read(fwJournal)
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "WARN: %s: skipped %d unreadable line(s)\n", fwJournal, skipped)
+	}
+	return journal.Summarize(entries, image), nil
+}
+
+// observedVersion returns the versions reported by targets on host, comma-separated, or ""
+// when none could be read.
+func observedVersion(ctx context.Context, host, user, pass string, targets []string) string {
+	var versions []string
+	for _, target := range targets {
+		if inv, err := redfish.GetFirmwareInventory(ctx, host, user, pass, fwInsecure, fwTimeout, target); err == nil && inv.Version != "" {
+			versions = append(versions, inv.Version)
+		}
+	}
+	return strings.Join(versions, ",")
+}
+
+// resumeHosts drops the hosts that the journal records as completed for this image. Hosts
+// left in flight by an interrupted run are re-checked: those that reached --expected-version
+// are recorded as skipped, and those whose BMC still reports the update task as running are
+// left alone. Everything else, including failed hosts, is updated again.
+func resumeHosts(parent context.Context, hosts []bmcHost, user, pass string, u fwUpdate, jw *journal.Writer) ([]bmcHost, error) {
+	states, err := readJournal(u.ImageURI)
+	if errors.Is(err, fs.ErrNotExist) {
+		return hosts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("--resume: %w", err)
+	}
+	byHost := make(map[string]journal.HostState, len(states))
+	for _, s := range states {
+		byHost[s.Host] = s
+	}
+
+	var out []bmcHost
+	for _, h := range hosts {
+		hu := u.forHost(h.Host)
+		s, ok := byHost[h.Host]
+		// With --catalog the journal is read for every image; only this host's image counts
+		ok = ok && s.Last.Image == hu.ImageURI
+		switch {
+		case !ok:
+			out = append(out, h)
+		case s.Completed():
+			fmt.Printf("%s: already %s according to the journal\n", h.label(), s.Last.Result)
+		case s.InFlight():
+			if !recheckInFlight(parent, h, user, pass, hu, jw) {
+				out = append(out, h)
+			}
+		default:
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+// recheckInFlight reports whether a host left in flight needs no new update.
+func recheckInFlight(parent context.Context, h bmcHost, user, pass string, u fwUpdate, jw *journal.Writer) bool {
+	ctx := parent
+	if fwTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, fwTimeout)
+		defer cancel()
+	}
+	if u.ExpectedVersion != "" && atVersion(ctx, h.Host, user, pass, u.Targets, u.ExpectedVersion) {
+		fmt.Printf("%s: was in flight and now reports version %s\n", h.label(), u.ExpectedVersion)
+		if jw != nil {
+			if err := jw.Record(journal.Entry{
+				Event: journal.EventFinish, Host: h.Host, Xname: h.Xname, Image: u.ImageURI, Targets: u.Targets,
+				Result: journal.ResultSkipped, Version: observedVersion(ctx, h.Host, user, pass, u.Targets),
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: journal: %v\n", h.Host, err)
+			}
+		}
+		return true
+	}
+	// The run was interrupted before the BMC's answer was recorded, so look for any
+	// update task rather than a specific one
+	if tasks, err := redfish.GetActiveUpdateTasks(ctx, h.Host, user, pass, fwInsecure, fwTimeout); err == nil && len(tasks) > 0 {
+		fmt.Printf("%s: update from the previous run is still in progress, not re-posting\n", h.label())
+		return true
+	}
+	fmt.Printf("%s: was in flight, updating again\n", h.label())
+	return false
+}
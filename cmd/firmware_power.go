@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bootstrap/internal/redfish"
+)
+
+// --reboot modes
+const (
+	rebootNever    = "never"    // leave node power alone
+	rebootAfter    = "after"    // restart nodes that are on once the update is triggered
+	rebootRequired = "required" // power nodes off gracefully before the update and back on after
+)
+
+var (
+	fwReboot        string
+	fwRequireOff    bool
+	fwPowerTimeout  time.Duration
+	fwPowerInterval time.Duration
+	fwTaskTimeout   time.Duration
+)
+
+// powerOptions is how an update handles node power.
+type powerOptions struct {
+	Reboot      string // one of the --reboot modes
+	RequireOff  bool
+	Timeout     time.Duration // per node, to power off or to power on and boot
+	Interval    time.Duration // poll interval for the update task and node power
+	TaskTimeout time.Duration // for the update task to finish before power is touched
+}
+
+// fwPowerOptions returns the power options of the firmware command flags.
+func fwPowerOptions() powerOptions {
+	return powerOptions{
+		Reboot:      fwReboot,
+		RequireOff:  fwRequireOff,
+		Timeout:     fwPowerTimeout,
+		Interval:    fwPowerInterval,
+		TaskTimeout: fwTaskTimeout,
+	}
+}
+
+// checkPowerFlags validates --reboot and --require-off.
+func checkPowerFlags() error {
+	switch fwReboot {
+	case rebootNever, rebootAfter, rebootRequired:
+	default:
+		return fmt.Errorf("invalid --reboot %q (want never, after or required)", fwReboot)
+	}
+	if fwRequireOff && fwReboot != rebootNever {
+		return fmt.Errorf("--require-off cannot be combined with --reboot %s", fwReboot)
+	}
+	return nil
+}
+
+// powerBeforeUpdate checks the ComputerSystems behind host before an update. With
+// RequireOff it fails if any system is on; with Reboot required it shuts the systems that
+// are on down gracefully and waits for them to power off. It returns the paths of the
+// systems it powered off, which powerAfterUpdate turns back on.
+func powerBeforeUpdate(parent context.Context, p powerOptions, host, user, pass string) ([]string, error) {
+	if !p.RequireOff && p.Reboot != rebootRequired {
+		return nil, nil
+	}
+	systems, err := redfish.GetSystemsPower(parent, host, user, pass, fwInsecure, fwTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("read power state: %w", err)
+	}
+	var on []string
+	for _, s := range systems {
+		if !s.Off() {
+			on = append(on, s.Path)
+		}
+	}
+	if p.RequireOff {
+		if len(on) > 0 {
+			return nil, fmt.Errorf("--require-off: not powered off: %s", strings.Join(on, ", "))
+		}
+		return nil, nil
+	}
+
+	var poweredOff []string
+	for _, path := range on {
+		if err := redfish.ResetSystem(parent, host, user, pass, fwInsecure, fwTimeout, path, "GracefulShutdown"); err != nil {
+			return poweredOff, fmt.Errorf("%s: GracefulShutdown: %w", path, err)
+		}
+		poweredOff = append(poweredOff, path)
+	}
+	for _, path := range poweredOff {
+		ctx, cancel := context.WithTimeout(parent, p.Timeout)
+		_, err := redfish.WaitSystemPower(ctx, host, user, pass, fwInsecure, fwTimeout, p.Interval, path, redfish.SystemPower.Off)
+		cancel()
+		if err != nil {
+			return poweredOff, fmt.Errorf("%s: did not power off: %w", path, err)
+		}
+	}
+	return poweredOff, nil
+}
+
+// powerAfterTask runs powerAfterUpdate once the BMC has finished the update task at taskURI
+// (or, without one, its running update tasks), so that nodes are not reset while the image
+// is still being transferred or flashed. When the task fails only the systems in poweredOff
+// are turned back on; when it does not finish within TaskTimeout node power is left as it is.
+func powerAfterTask(parent context.Context, p powerOptions, host, user, pass, taskURI string, poweredOff []string) error {
+	if p.Reboot == rebootNever {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(parent, p.TaskTimeout)
+	_, err := redfish.WaitUpdateTask(ctx, host, user, pass, fwInsecure, fwTimeout, p.Interval, taskURI)
+	cancel()
+	if err != nil {
+		if !errors.Is(err, redfish.ErrTaskFailed) {
+			return fmt.Errorf("update task: %w; node power left as it is", err)
+		}
+		if len(poweredOff) > 0 {
+			// Nothing will be applied, so only bring the nodes back
+			if perr := powerAfterUpdate(parent, p, host, user, pass, poweredOff); perr != nil {
+				return fmt.Errorf("update task: %w; power: %v", err, perr)
+			}
+		}
+		return fmt.Errorf("update task: %w", err)
+	}
+	if err := powerAfterUpdate(parent, p, host, user, pass, poweredOff); err != nil {
+		return fmt.Errorf("power: %w", err)
+	}
+	return nil
+}
+
+// powerAfterUpdate restores node power once the update is triggered. With Reboot after
+// every system that is on is restarted gracefully; with Reboot required the systems in
+// poweredOff are turned back on. It then waits for each of those systems to settle (powered
+// on and, where the BMC reports BootProgress, booted into the OS) and returns an error
+// naming the systems that did not come back within Timeout.
+func powerAfterUpdate(parent context.Context, p powerOptions, host, user, pass string, poweredOff []string) error {
+	type pending struct {
+		path string
+		// seenDown is false when the system must first be seen leaving the OS, so that the
+		// state before a restart is not mistaken for the system having come back
+		seenDown bool
+	}
+	var wait []*pending
+	switch p.Reboot {
+	case rebootAfter:
+		systems, err := redfish.GetSystemsPower(parent, host, user, pass, fwInsecure, fwTimeout)
+		if err != nil {
+			return fmt.Errorf("read power state: %w", err)
+		}
+		for _, s := range systems {
+			if !s.On() {
+				continue
+			}
+			if err := redfish.ResetSystem(parent, host, user, pass, fwInsecure, fwTimeout, s.Path, "GracefulRestart"); err != nil {
+				return fmt.Errorf("%s: GracefulRestart: %w", s.Path, err)
+			}
+			// Without BootProgress a restart cannot be told apart from a system that stayed up
+			wait = append(wait, &pending{path: s.Path, seenDown: s.BootProgress == ""})
+		}
+	case rebootRequired:
+		for _, path := range poweredOff {
+			if err := redfish.ResetSystem(parent, host, user, pass, fwInsecure, fwTimeout, path, "On"); err != nil {
+				return fmt.Errorf("%s: power on: %w", path, err)
+			}
+			wait = append(wait, &pending{path: path, seenDown: true})
+		}
+	}
+
+	var notBack []string
+	for _, w := range wait {
+		ctx, cancel := context.WithTimeout(parent, p.Timeout)
+		_, err := redfish.WaitSystemPower(ctx, host, user, pass, fwInsecure, fwTimeout, p.Interval, w.path, func(s redfish.SystemPower) bool {
+			if !s.Booted() {
+				w.seenDown = true
+				return false
+			}
+			return w.seenDown
+		})
+		cancel()
+		if err != nil {
+			notBack = append(notBack, fmt.Sprintf("%s (%v)", w.path, err))
+		}
+	}
+	if len(notBack) > 0 {
+		return fmt.Errorf("did not come back: %s", strings.Join(notBack, ", "))
+	}
+	return nil
+}
+
+func init() {
+	firmwareCmd.Flags().StringVar(&fwReboot, "reboot", rebootNever, "node power handling for updates that apply on reboot, such as BIOS: never, after (gracefully restart nodes that are on once triggered) or required (gracefully power nodes off before the update and back on after)")
+	firmwareCmd.Flags().BoolVar(&fwRequireOff, "require-off", false, "fail hosts whose nodes (ComputerSystems) are not powered off instead of updating them")
+	firmwareCmd.Flags().DurationVar(&fwPowerTimeout, "power-timeout", 10*time.Minute, "maximum time to wait for a node to power off, or to power on and boot")
+	firmwareCmd.Flags().DurationVar(&fwPowerInterval, "power-interval", 10*time.Second, "poll interval while waiting for the update task and node power state")
+	firmwareCmd.Flags().DurationVar(&fwTaskTimeout, "task-timeout", 30*time.Minute, "maximum time to wait for the update task to finish before --reboot touches node power; nodes are left as they are when it does not")
+}
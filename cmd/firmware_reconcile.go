@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"bootstrap/internal/baseline"
+	"bootstrap/internal/redfish"
+	"bootstrap/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	recBaseline     string
+	recImageBaseURL string
+)
+
+// reconcileItem is one row of the reconcile plan: a host target compared to the baseline.
+type reconcileItem struct {
+	Host      bmcHost
+	Component int
+	Target    string
+	Current   string
+	Desired   string
+	Action    string // one of: ok, update, error
+	Error     string
+}
+
+// reconcileComponent is a baseline component with its targets and image resolved.
+type reconcileComponent struct {
+	baseline.Component
+	update fwUpdate
+}
+
+var firmwareReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Compare live firmware against a baseline file and apply only the needed updates",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if recBaseline == "" {
+			return errors.New("--baseline is required")
+		}
+		doc, err := baseline.Load(recBaseline)
+		if err != nil {
+			return err
+		}
+		applyOpts, err := applyTimeOptions()
+		if err != nil {
+			return err
+		}
+		pol, err := versionPolicy()
+		if err != nil {
+			return err
+		}
+		components := make([]reconcileComponent, 0, len(doc.Components))
+		for _, c := range doc.Components {
+			targets := c.Targets
+			if len(targets) == 0 {
+				if targets, err = defaultTargets(c.Type); err != nil {
+					return fmt.Errorf("%s: %w", c.Name, err)
+				}
+			}
+			image, err := c.Image(recImageBaseURL)
+			if err != nil {
+				return err
+			}
+			protocol := c.Protocol
+			if protocol == "" {
+				protocol = fwProtocol
+			}
+			components = append(components, reconcileComponent{
+				Component: c,
+				update: fwUpdate{
+					ImageURI:        image,
+					Targets:         targets,
+					Protocol:        protocol,
+					ExpectedVersion: c.Version,
+					Apply:           applyOpts,
+					Policy:          pol,
+				},
+			})
+		}
+
+		user := os.Getenv("REDFISH_USER")
+		pass := os.Getenv("REDFISH_PASSWORD")
+		if user == "" || pass == "" {
+			return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
+		}
+		hosts, err := loadBMCHosts(fwFile, fwHostsCSV)
+		if err != nil {
+			return err
+		}
+
+		plan := reconcilePlan(cmd.Context(), hosts, components, user, pass)
+		var errs []error
+		if unchecked := printReconcilePlan(plan, components); unchecked > 0 {
+			errs = append(errs, fmt.Errorf("%d target(s) could not be checked", unchecked))
+		}
+
+		if fwDryRun {
+			return errors.Join(errs...)
+		}
+		jw, err := openJournal()
+		if err != nil {
+			return err
+		}
+		if jw != nil {
+			defer jw.Close() //nolint:errcheck
+		}
+
+		// Apply component by component in baseline order, only to hosts that need it
+		failed := 0
+		for ci, c := range components {
+			var need []bmcHost
+			for _, it := range plan {
+				if it.Component == ci && it.Action == "update" && (len(need) == 0 || need[len(need)-1] != it.Host) {
+					need = append(need, it.Host)
+				}
+			}
+			if len(need) == 0 {
+				continue
+			}
+			fmt.Printf("Updating %s to %s on %d host(s)\n", c.Name, c.Version, len(need))
+			failed += len(runFirmwareWave(cmd.Context(), need, user, pass, c.update, jw))
+		}
+		if failed > 0 {
+			errs = append(errs, fmt.Errorf("%d firmware update(s) failed", failed))
+		}
+		return errors.Join(errs...)
+	},
+}
+
+// reconcilePlan reads the live FirmwareInventory of every baseline target on every matching
+// host within the --batch-size and per-cabinet/chassis limits, and returns plan rows in host
+// then component order.
+func reconcilePlan(parent context.Context, hosts []bmcHost, components []reconcileComponent, user, pass string) []reconcileItem {
+	perHost := make([][]reconcileItem, len(hosts))
+	runHosts(hosts, fwLimits(), func(i int, h bmcHost) {
+		ctx := parent
+		if fwTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, fwTimeout)
+			defer cancel()
+		}
+		for ci, c := range components {
+			if !c.Matches(h.Xname, h.Host) {
+				continue
+			}
+			targets, err := resolveTargets(ctx, h.Host, user, pass, c.update.Targets)
+			if err != nil {
+				perHost[i] = append(perHost[i], reconcileItem{Host: h, Component: ci, Target: strings.Join(c.update.Targets, ","),
+					Desired: c.Version, Action: "error", Error: err.Error()})
+				continue
+			}
+			for _, target := range targets {
+				it := reconcileItem{Host: h, Component: ci, Target: target, Desired: c.Version}
+				inv, err := redfish.GetFirmwareInventory(ctx, h.Host, user, pass, fwInsecure, fwTimeout, target)
+				switch {
+				case err != nil:
+					it.Action = "error"
+					it.Error = err.Error()
+				case version.Equal(versionParser(), inv.Version, c.Version) && !fwForce:
+					it.Current = inv.Version
+					it.Action = "ok"
+				default:
+					it.Current = inv.Version
+					it.Action = "update"
+				}
+				perHost[i] = append(perHost[i], it)
+			}
+		}
+	})
+
+	var plan []reconcileItem
+	for _, items := range perHost {
+		plan = append(plan, items...)
+	}
+	return plan
+}
+
+// printReconcilePlan prints the plan table and summary and returns the number of error rows.
+func printReconcilePlan(plan []reconcileItem, components []reconcileComponent) int {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tCOMPONENT\tTARGET\tCURRENT\tDESIRED\tACTION") //nolint:errcheck
+	counts := map[string]int{}
+	for _, it := range plan {
+		current := it.Current
+		if current == "" {
+			current = "(unknown)"
+		}
+		action := it.Action
+		if it.Error != "" {
+			action += ": " + it.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", it.Host.label(), components[it.Component].Name, it.Target, current, it.Desired, action) //nolint:errcheck
+		counts[it.Action]++
+	}
+	tw.Flush() //nolint:errcheck
+	fmt.Printf("Plan: %d to update, %d up to date, %d error(s)\n", counts["update"], counts["ok"], counts["error"])
+	return counts["error"]
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareReconcileCmd)
+	firmwareReconcileCmd.Flags().StringVar(&recBaseline, "baseline", "", "baseline YAML listing components, required versions and images (required)")
+	firmwareReconcileCmd.Flags().StringVar(&recImageBaseURL, "image-base-url", "", "base URL that image_file entries in the baseline are resolved against")
+}
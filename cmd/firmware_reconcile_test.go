@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFirmwareReconcileAppliesOnlyNeededUpdates(t *testing.T) {
+	var mu sync.Mutex
+	var postedImages []string
+	var biosDown atomic.Bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			postedImages = append(postedImages, body["ImageURI"].(string))
+			mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasSuffix(r.URL.Path, "/FirmwareInventory"):
+			json.NewEncoder(w).Encode(map[string]any{"Members": []map[string]string{ //nolint:errcheck
+				{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BMC"},
+				{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS"},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/FirmwareInventory/BMC"):
+			json.NewEncoder(w).Encode(map[string]any{"Version": "nc.1.0.0"}) //nolint:errcheck
+		case strings.HasSuffix(r.URL.Path, "/FirmwareInventory/Node0.BIOS") && biosDown.Load():
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case strings.HasSuffix(r.URL.Path, "/FirmwareInventory/Node0.BIOS"):
+			json.NewEncoder(w).Encode(map[string]any{"Version": "1.6"}) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	dir := t.TempDir()
+	recBaseline = filepath.Join(dir, "baseline.yaml")
+	baselineYAML := `components:
+  - name: bmc
+    type: bmc
+    version: nc.1.2.0
+    image_file: bmc-1.2.0.bin
+  - name: bios
+    targets: ["*/FirmwareInventory/Node*.BIOS"]
+    version: "1.6"
+    image_uri: http://10.0.0.1/bios-1.6.cap
+`
+	if err := os.WriteFile(recBaseline, []byte(baselineYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	recImageBaseURL = "http://10.0.0.1/images"
+	fwFile = makeInventoryFile(t, strings.TrimPrefix(server.URL, "https://"))
+	fwHostsCSV = ""
+	fwProtocol = "HTTP"
+	fwInsecure = true
+	fwTimeout = 5 * time.Second
+	fwDryRun = false
+	fwForce = false
+	fwBatchSize = 0
+	fwApplyTime = ""
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	output, err := runCmd(t, firmwareReconcileCmd)
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	if !strings.Contains(output, "Plan: 1 to update, 1 up to date, 0 error(s)") {
+		t.Fatalf("unexpected plan summary:\n%s", output)
+	}
+	if len(postedImages) != 1 || postedImages[0] != "http://10.0.0.1/images/bmc-1.2.0.bin" {
+		t.Fatalf("expected only the BMC image to be posted, got %v\nOutput: %s", postedImages, output)
+	}
+	if !strings.Contains(output, "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS") {
+		t.Fatalf("expected the bios pattern to resolve to Node0.BIOS:\n%s", output)
+	}
+
+	// A target that cannot be read fails the run, in dry-run and apply mode alike
+	biosDown.Store(true)
+	postedImages = nil
+	fwDryRun = true
+	output, err = runCmd(t, firmwareReconcileCmd)
+	if err == nil || !strings.Contains(err.Error(), "1 target(s) could not be checked") {
+		t.Fatalf("dry-run: expected the unreadable target to fail the run, got %v\nOutput: %s", err, output)
+	}
+	fwDryRun = false
+	output, err = runCmd(t, firmwareReconcileCmd)
+	if err == nil || !strings.Contains(err.Error(), "1 target(s) could not be checked") {
+		t.Fatalf("apply: expected the unreadable target to fail the run, got %v\nOutput: %s", err, output)
+	}
+	if len(postedImages) != 1 {
+		t.Fatalf("expected the BMC update to still be applied, got %v\nOutput: %s", postedImages, output)
+	}
+}
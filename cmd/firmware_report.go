@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/journal"
+
+	"github.com/spf13/cobra"
+)
+
+var firmwareReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize a firmware rollout journal",
+	Long: "Summarize the latest state of every host in a --journal written by the firmware command.\n" +
+		"With --image-uri, only entries for that image are considered.",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if fwJournal == "" {
+			return errors.New("--journal is required")
+		}
+		states, err := readJournal(fwImageURI)
+		if err != nil {
+			return err
+		}
+		if len(states) == 0 {
+			return fmt.Errorf("%s has no entries", fwJournal)
+		}
+
+		counts := map[string]int{}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "HOST\tSTATE\tVERSION\tTASK\tFINISHED\tERROR") //nolint:errcheck
+		for _, s := range states {
+			name := s.Host
+			if s.Xname != "" {
+				name = s.Xname + " (" + s.Host + ")"
+			}
+			finished := ""
+			if !s.InFlight() {
+				finished = s.Finished.Local().Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", name, s.State(), s.Last.Version, s.Last.TaskURI, finished, s.Last.Error) //nolint:errcheck
+			counts[s.State()]++
+		}
+		tw.Flush() //nolint:errcheck
+		summary := fmt.Sprintf("Hosts: %d (%d triggered, %d skipped, %d failed, %d in-flight",
+			len(states), counts[journal.ResultTriggered], counts[journal.ResultSkipped], counts[journal.ResultFailed], counts["in-flight"])
+		// Verification outcomes only appear in journals written with --verify
+		for _, r := range []string{journal.ResultUpdated, journal.ResultUnchanged, journal.ResultRolledBack, journal.ResultUnreachable} {
+			if counts[r] > 0 {
+				summary += fmt.Sprintf(", %d %s", counts[r], r)
+			}
+		}
+		fmt.Println(summary + ")")
+		return nil
+	},
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareReportCmd)
+}
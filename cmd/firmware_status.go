@@ -9,28 +9,62 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
-	"bootstrap/internal/inventory"
 	"bootstrap/internal/redfish"
+	"bootstrap/internal/version"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
 	// reuse firmware flags (made persistent)
 	fwStatusInterval time.Duration
 	fwFormat         string
+	fwRequireVersion bool
 )
 
+// hostSummary is the status of one firmware target on one host.
+type hostSummary struct {
+	Xname            string `json:"xname,omitempty" yaml:"xname,omitempty"`
+	Host             string `json:"host" yaml:"host"`
+	Target           string `json:"target" yaml:"target"`
+	ObservedVersion  string `json:"observed_version" yaml:"observed_version"`
+	RequestedVersion string `json:"requested_version,omitempty" yaml:"requested_version,omitempty"`
+	Status           string `json:"status" yaml:"status"`                     // one of: in-progress, staged, error, idle
+	Policy           string `json:"policy,omitempty" yaml:"policy,omitempty"` // below-min, above-max or unparsable under --min-version/--max-version
+	Error            string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// label names the host target in human-readable output, preferring the xname.
+func (s hostSummary) label() string {
+	if s.Xname == "" {
+		return s.Host
+	}
+	return s.Xname
+}
+
+// sortSummaries orders summaries by xname (or host when there is none), then target.
+func sortSummaries(summaries []hostSummary) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if a, b := summaries[i].label(), summaries[j].label(); a != b {
+			return a < b
+		}
+		if summaries[i].Host != summaries[j].Host {
+			return summaries[i].Host < summaries[j].Host
+		}
+		return summaries[i].Target < summaries[j].Target
+	})
+}
+
 var firmwareStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Query BMC firmware versions and in-progress updates",
@@ -41,36 +75,21 @@ var firmwareStatusCmd = &cobra.Command{
 			return errors.New("REDFISH_USER and REDFISH_PASSWORD env vars are required")
 		}
 
-		// Determine hosts to target (reuse logic from firmware.go)
-		hosts := []string{}
-		if strings.TrimSpace(fwHostsCSV) != "" {
-			for _, h := range strings.Split(fwHostsCSV, ",") {
-				h = strings.TrimSpace(h)
-				if h != "" {
-					hosts = append(hosts, h)
-				}
-			}
-		} else {
-			raw, err := os.ReadFile(fwFile)
-			if err != nil {
-				return err
-			}
-			var doc inventory.FileFormat
-			if err := yaml.Unmarshal(raw, &doc); err != nil {
-				return err
-			}
-			if len(doc.BMCs) == 0 {
-				return fmt.Errorf("input must contain non-empty bmcs[]")
-			}
-			for _, b := range doc.BMCs {
-				host := b.IP
-				if host == "" {
-					host = b.Xname
-				}
-				hosts = append(hosts, host)
-			}
+		if fwRequireVersion && fwExpectedVersion == "" {
+			return errors.New("--require-version needs --expected-version")
+		}
+		if _, ok := statusWriters[strings.ToLower(fwFormat)]; !ok && fwFormat != "" {
+			return fmt.Errorf("unknown --format %q (want text, table, json, ndjson, yaml, csv or markdown)", fwFormat)
+		}
+		pol, err := versionPolicy()
+		if err != nil {
+			return err
 		}
 
+		hosts, err := loadBMCHosts(fwFile, fwHostsCSV)
+		if err != nil {
+			return err
+		}
 		if len(hosts) == 0 {
 			return fmt.Errorf("no hosts to query")
 		}
@@ -83,230 +102,284 @@ var firmwareStatusCmd = &cobra.Command{
 				// default to bmc when not specified
 				typeName = "bmc"
 			}
-			var err error
 			targets, err = defaultTargets(typeName)
 			if err != nil {
 				return err
 			}
 		}
 
-		// Results aggregation
-		var mu sync.Mutex
-		versionCounts := map[string]int{}
-		inProgress := int32(0)
-		errorsList := map[string]string{}
+		if fwWatch {
+			return watchFirmwareStatus(cmd.Context(), hosts, targets, user, pass)
+		}
 
-		// Collect per-target summaries for JSON output
-		type hostSummary struct {
-			Host             string `json:"host"`
-			Target           string `json:"target"`
-			ObservedVersion  string `json:"observed_version"`
-			RequestedVersion string `json:"requested_version,omitempty"`
-			Status           string `json:"status"` // one of: in-progress, error, idle
-			Error            string `json:"error,omitempty"`
+		hostSummaries := collectFirmwareStatus(cmd.Context(), hosts, targets, user, pass)
+		sortSummaries(hostSummaries)
+		for i, s := range hostSummaries {
+			if s.Error == "" {
+				hostSummaries[i].Policy = pol.Check(s.ObservedVersion)
+			}
+		}
+		if fwHistory != "" {
+			if err := recordHistory(hostSummaries); err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: --history: %v\n", err)
+			}
 		}
-		var hostSummaries []hostSummary
 
-		sem := make(chan struct{}, max(1, fwBatchSize))
-		var wg sync.WaitGroup
-		for _, host := range hosts {
-			wg.Add(1)
-			h := host
-			go func() {
-				defer wg.Done()
-				sem <- struct{}{}
-				defer func() { <-sem }()
+		if w := statusWriters[strings.ToLower(fwFormat)]; w != nil {
+			if err := w(os.Stdout, hostSummaries); err != nil {
+				return err
+			}
+		} else {
+			printStatusSummary(len(hosts), hostSummaries)
+		}
+		if err := statusExitError(hostSummaries); err != nil {
+			// A gating failure is not a usage mistake
+			cmd.SilenceUsage = true
+			return err
+		}
+		return nil
+	},
+}
 
-				ctx := cmd.Context()
-				if fwTimeout > 0 {
-					var cancel context.CancelFunc
-					ctx, cancel = context.WithTimeout(ctx, fwTimeout)
-					defer cancel()
-				}
+// collectFirmwareStatus queries UpdateService, TaskService and FirmwareInventory on each
+// host within the --batch-size and per-cabinet/chassis limits, and returns one summary per
+// host target.
+func collectFirmwareStatus(parent context.Context, hosts []bmcHost, targets []string, user, pass string) []hostSummary {
+	var mu sync.Mutex
+	var hostSummaries []hostSummary
 
-				// Check UpdateService first (preferred source for overall update activity)
-				var perr string
-				var anyInProgress bool
-				us, err := redfish.GetUpdateServiceStatus(ctx, h, user, pass, fwInsecure, fwTimeout)
-				if err == nil {
-					health := strings.ToLower(us.Health)
-					state := strings.ToLower(us.State)
-					if health != "ok" {
-						// collect condition messages as errors
-						for _, c := range us.Conditions {
-							if c.MessageID != "" {
-								if perr == "" {
-									perr = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-								} else {
-									perr = perr + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-								}
-							} else {
-								if perr == "" {
-									perr = c.Message
-								} else {
-									perr = perr + "; " + c.Message
-								}
-							}
+	runHosts(hosts, fwLimits(), func(_ int, b bmcHost) {
+		h := b.Host
+		ctx := parent
+		if fwTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, fwTimeout)
+			defer cancel()
+		}
+
+		// Check UpdateService first (preferred source for overall update activity)
+		var perr string
+		var anyInProgress bool
+		us, err := redfish.GetUpdateServiceStatus(ctx, h, user, pass, fwInsecure, fwTimeout)
+		if err == nil {
+			health := strings.ToLower(us.Health)
+			state := strings.ToLower(us.State)
+			if health != "ok" {
+				// collect condition messages as errors
+				for _, c := range us.Conditions {
+					if c.MessageID != "" {
+						if perr == "" {
+							perr = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
+						} else {
+							perr = perr + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
+						}
+					} else {
+						if perr == "" {
+							perr = c.Message
+						} else {
+							perr = perr + "; " + c.Message
 						}
-					} else if state == "updating" {
-						anyInProgress = true
 					}
 				}
+			} else if state == "updating" {
+				anyInProgress = true
+			}
+		}
 
-				// If UpdateService and inventory did not indicate progress, check TaskService for running jobs
-				if !anyInProgress {
-					if tasks, err := redfish.GetActiveUpdateTasks(ctx, h, user, pass, fwInsecure, fwTimeout); err == nil {
-						if len(tasks) > 0 {
-							anyInProgress = true
-						}
-					}
+		// If UpdateService and inventory did not indicate progress, check TaskService for running jobs
+		if !anyInProgress {
+			if tasks, err := redfish.GetActiveUpdateTasks(ctx, h, user, pass, fwInsecure, fwTimeout); err == nil {
+				if len(tasks) > 0 {
+					anyInProgress = true
 				}
+			}
+		}
 
-				// Query each target separately and record per-target summaries
-				for _, target := range targets {
-					var perrTarget string
-					var verTarget string
-					var anyInProgressTarget bool
+		// Tasks waiting for their apply time (OnReset, maintenance window) mean an image is staged
+		var anyStaged bool
+		if !anyInProgress {
+			if tasks, err := redfish.GetPendingUpdateTasks(ctx, h, user, pass, fwInsecure, fwTimeout); err == nil {
+				anyStaged = len(tasks) > 0
+			}
+		}
 
-					inv, err := redfish.GetFirmwareInventory(ctx, h, user, pass, fwInsecure, fwTimeout, target)
-					if err != nil {
-						perrTarget = err.Error()
-					} else {
-						verTarget = inv.Version
-						// If the inventory reports a non-OK Health, treat as error and include conditions
-						if strings.ToLower(inv.Health) != "" && !strings.EqualFold(inv.Health, "OK") {
-							if len(inv.Conditions) > 0 {
-								for _, c := range inv.Conditions {
-									if c.MessageID != "" {
-										if perrTarget == "" {
-											perrTarget = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-										} else {
-											perrTarget = perrTarget + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-										}
-									} else {
-										if perrTarget == "" {
-											perrTarget = c.Message
-										} else {
-											perrTarget = perrTarget + "; " + c.Message
-										}
-									}
-								}
-							} else {
-								perrTarget = fmt.Sprintf("health: %s", inv.Health)
-							}
-						}
+		// Query each target separately and record per-target summaries
+		for _, target := range targets {
+			var perrTarget string
+			var verTarget string
+			var anyInProgressTarget bool
+			var stagedTarget bool
 
-						st := strings.ToLower(inv.State)
-						if st != "" && st != "enabled" && st != "ok" {
-							anyInProgressTarget = true
-						}
+			inv, err := redfish.GetFirmwareInventory(ctx, h, user, pass, fwInsecure, fwTimeout, target)
+			if err != nil {
+				perrTarget = err.Error()
+			} else {
+				verTarget = inv.Version
+				stagedTarget = inv.Staged()
+				// If the inventory reports a non-OK Health, treat as error and include conditions
+				if strings.ToLower(inv.Health) != "" && !strings.EqualFold(inv.Health, "OK") {
+					if len(inv.Conditions) > 0 {
 						for _, c := range inv.Conditions {
-							m := strings.ToLower(c.Message)
-							if c.Severity == "Critical" || strings.Contains(m, "failed") || strings.Contains(m, "error") {
-								if c.MessageID != "" {
-									if perrTarget == "" {
-										perrTarget = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-									} else {
-										perrTarget = perrTarget + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-									}
+							if c.MessageID != "" {
+								if perrTarget == "" {
+									perrTarget = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
 								} else {
-									if perrTarget == "" {
-										perrTarget = c.Message
-									} else {
-										perrTarget = perrTarget + "; " + c.Message
-									}
+									perrTarget = perrTarget + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
+								}
+							} else {
+								if perrTarget == "" {
+									perrTarget = c.Message
+								} else {
+									perrTarget = perrTarget + "; " + c.Message
 								}
-								continue
-							}
-							if strings.Contains(m, "in progress") || strings.Contains(m, "install") || strings.Contains(m, "installing") || strings.Contains(m, "running") || strings.Contains(m, "downloading") || strings.Contains(m, "download in progress") {
-								anyInProgressTarget = true
 							}
 						}
+					} else {
+						perrTarget = fmt.Sprintf("health: %s", inv.Health)
 					}
+				}
 
-					// Determine observed version fallback
-					if verTarget == "" {
-						verTarget = "(unknown)"
-					}
-
-					// Build status for this target: combine host-level and target-level info
-					status := "idle"
-					// perr (host-level) may have been set from UpdateService; include it
-					combinedErr := perr
-					if perrTarget != "" {
-						if combinedErr == "" {
-							combinedErr = perrTarget
+				st := strings.ToLower(inv.State)
+				if st != "" && st != "enabled" && st != "ok" && !stagedTarget {
+					anyInProgressTarget = true
+				}
+				for _, c := range inv.Conditions {
+					m := strings.ToLower(c.Message)
+					if c.Severity == "Critical" || strings.Contains(m, "failed") || strings.Contains(m, "error") {
+						if c.MessageID != "" {
+							if perrTarget == "" {
+								perrTarget = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
+							} else {
+								perrTarget = perrTarget + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
+							}
 						} else {
-							combinedErr = combinedErr + "; " + perrTarget
+							if perrTarget == "" {
+								perrTarget = c.Message
+							} else {
+								perrTarget = perrTarget + "; " + c.Message
+							}
 						}
+						continue
 					}
-					if combinedErr != "" {
-						status = "error"
-					} else if anyInProgress || anyInProgressTarget {
-						status = "in-progress"
-					}
-
-					// Update aggregates and per-target list
-					mu.Lock()
-					versionCounts[verTarget]++
-					if combinedErr != "" {
-						// use host+target key so multiple targets per host are visible
-						errorsList[fmt.Sprintf("%s %s", h, target)] = combinedErr
+					if stagedTarget {
+						continue
 					}
-					if status == "in-progress" {
-						atomic.AddInt32(&inProgress, 1)
+					if strings.Contains(m, "in progress") || strings.Contains(m, "install") || strings.Contains(m, "installing") || strings.Contains(m, "running") || strings.Contains(m, "downloading") || strings.Contains(m, "download in progress") {
+						anyInProgressTarget = true
 					}
-					hostSummaries = append(hostSummaries, hostSummary{
-						Host:             h,
-						Target:           target,
-						ObservedVersion:  verTarget,
-						RequestedVersion: fwExpectedVersion,
-						Status:           status,
-						Error:            combinedErr,
-					})
-					mu.Unlock()
 				}
-			}()
-		}
-		wg.Wait()
+			}
 
-		// JSON format option
-		if strings.EqualFold(fwFormat, "json") {
-			out, err := json.MarshalIndent(hostSummaries, "", "  ")
-			if err != nil {
-				return err
+			// Determine observed version fallback
+			if verTarget == "" {
+				verTarget = "(unknown)"
+			}
+
+			// Build status for this target: combine host-level and target-level info
+			status := "idle"
+			// perr (host-level) may have been set from UpdateService; include it
+			combinedErr := perr
+			if perrTarget != "" {
+				if combinedErr == "" {
+					combinedErr = perrTarget
+				} else {
+					combinedErr = combinedErr + "; " + perrTarget
+				}
+			}
+			if combinedErr != "" {
+				status = "error"
+			} else if anyInProgress || anyInProgressTarget {
+				status = "in-progress"
+			} else if anyStaged || stagedTarget {
+				status = "staged"
 			}
-			fmt.Println(string(out))
-			return nil
+
+			mu.Lock()
+			hostSummaries = append(hostSummaries, hostSummary{
+				Xname:            b.Xname,
+				Host:             h,
+				Target:           target,
+				ObservedVersion:  verTarget,
+				RequestedVersion: fwExpectedVersion,
+				Status:           status,
+				Error:            combinedErr,
+			})
+			mu.Unlock()
 		}
+	})
+	return hostSummaries
+}
 
-		// Print human-readable summary
-		fmt.Println("Firmware status summary:")
-		if strings.EqualFold(fwType, "bios") {
-			// For BIOS checks, report both BMC count and total targets checked
-			fmt.Printf("  Total BMCs: %d\n", len(hosts))
-			fmt.Printf("  Total BIOS targets checked: %d\n", len(hostSummaries))
-		} else {
-			fmt.Printf("  Total hosts: %d\n", len(hosts))
+// printStatusSummary prints the human-readable status summary for hostCount hosts.
+func printStatusSummary(hostCount int, hostSummaries []hostSummary) {
+	versionCounts := map[string]int{}
+	inProgress := 0
+	errorsList := map[string]string{}
+	var stagedList, policyList []string
+	for _, s := range hostSummaries {
+		if s.Policy != "" {
+			policyList = append(policyList, fmt.Sprintf("%s %s: %s (%s)", s.label(), s.Target, s.ObservedVersion, s.Policy))
 		}
-		fmt.Printf("  In-progress updates: %d\n", atomic.LoadInt32(&inProgress))
-		fmt.Println("  Versions:")
-		for v, c := range versionCounts {
-			fmt.Printf("    %s: %d\n", v, c)
+		versionCounts[s.ObservedVersion]++
+		if s.Error != "" {
+			// use host+target key so multiple targets per host are visible
+			errorsList[fmt.Sprintf("%s %s", s.label(), s.Target)] = s.Error
 		}
-		if len(errorsList) > 0 {
-			fmt.Println("  Errors:")
-			for h, e := range errorsList {
-				fmt.Printf("    %s: %s\n", h, e)
-			}
+		switch s.Status {
+		case "in-progress":
+			inProgress++
+		case "staged":
+			stagedList = append(stagedList, fmt.Sprintf("%s %s", s.label(), s.Target))
 		}
+	}
 
-		return nil
-	},
+	fmt.Println("Firmware status summary:")
+	if strings.EqualFold(fwType, "bios") {
+		// For BIOS checks, report both BMC count and total targets checked
+		fmt.Printf("  Total BMCs: %d\n", hostCount)
+		fmt.Printf("  Total BIOS targets checked: %d\n", len(hostSummaries))
+	} else {
+		fmt.Printf("  Total hosts: %d\n", hostCount)
+	}
+	fmt.Printf("  In-progress updates: %d\n", inProgress)
+	fmt.Printf("  Staged updates awaiting apply: %d\n", len(stagedList))
+	fmt.Println("  Versions:")
+	versions := slices.Collect(maps.Keys(versionCounts))
+	p := versionParser()
+	slices.SortFunc(versions, func(a, b string) int {
+		if c, ok := version.CompareStrings(p, a, b); ok && c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	for _, v := range versions {
+		fmt.Printf("    %s: %d\n", v, versionCounts[v])
+	}
+	if len(stagedList) > 0 {
+		fmt.Println("  Staged:")
+		for _, s := range stagedList {
+			fmt.Printf("    %s\n", s)
+		}
+	}
+	if len(policyList) > 0 {
+		fmt.Printf("  Outside version policy: %d\n", len(policyList))
+		for _, s := range policyList {
+			fmt.Printf("    %s\n", s)
+		}
+	}
+	if len(errorsList) > 0 {
+		fmt.Println("  Errors:")
+		for _, h := range slices.Sorted(maps.Keys(errorsList)) {
+			fmt.Printf("    %s: %s\n", h, errorsList[h])
+		}
+	}
 }
 
 func init() {
 	firmwareCmd.AddCommand(firmwareStatusCmd)
-	firmwareStatusCmd.Flags().DurationVar(&fwStatusInterval, "interval", 5*time.Second, "poll interval (not used in single-run summary, reserved for future watch command)")
-	firmwareStatusCmd.Flags().StringVar(&fwFormat, "format", "", "output format: json")
+	firmwareStatusCmd.Flags().DurationVar(&fwStatusInterval, "interval", 5*time.Second, "poll interval for --watch")
+	firmwareStatusCmd.Flags().StringVar(&fwFormat, "format", "", "output format: text (default), table, json, ndjson, yaml, csv or markdown")
+	firmwareStatusCmd.Flags().BoolVar(&fwRequireVersion, "require-version", false, "exit non-zero when any target does not report --expected-version (compared using --version-scheme)")
+	firmwareStatusCmd.Flags().BoolVar(&fwWatch, "watch", false, "re-poll every --interval and redraw a live table until all hosts reach --expected-version, --watch-timeout passes, or Ctrl-C")
+	firmwareStatusCmd.Flags().DurationVar(&fwWatchTimeout, "watch-timeout", 0, "stop watching after this long (0 = no limit)")
 }
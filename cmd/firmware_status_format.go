@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"bootstrap/internal/version"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Exit codes returned by firmware status so pipelines can tell failures apart.
+const (
+	exitStatusError   = 2 // at least one host target is in error
+	exitStatusVersion = 3 // a target is outside --min-version/--max-version, or not at --expected-version with --require-version
+)
+
+// statusWriters maps --format values to their writers. The empty format and "text" use
+// the human-readable summary instead.
+var statusWriters = map[string]func(io.Writer, []hostSummary) error{
+	"text":     nil,
+	"table":    writeStatusTable,
+	"json":     writeStatusJSON,
+	"ndjson":   writeStatusNDJSON,
+	"yaml":     writeStatusYAML,
+	"csv":      writeStatusCSV,
+	"markdown": writeStatusMarkdown,
+}
+
+var statusColumns = []string{"xname", "host", "target", "status", "observed_version", "requested_version", "policy", "error"}
+
+func (s hostSummary) row() []string {
+	return []string{s.Xname, s.Host, s.Target, s.Status, s.ObservedVersion, s.RequestedVersion, s.Policy, s.Error}
+}
+
+func writeStatusTable(w io.Writer, summaries []hostSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "XNAME\tHOST\tTARGET\tSTATUS\tVERSION\tREQUESTED\tPOLICY\tERROR") //nolint:errcheck
+	for _, s := range summaries {
+		fmt.Fprintln(tw, strings.Join(s.row(), "\t")) //nolint:errcheck
+	}
+	return tw.Flush()
+}
+
+func writeStatusJSON(w io.Writer, summaries []hostSummary) error {
+	if summaries == nil {
+		summaries = []hostSummary{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(summaries)
+}
+
+func writeStatusNDJSON(w io.Writer, summaries []hostSummary) error {
+	enc := json.NewEncoder(w)
+	for _, s := range summaries {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeStatusYAML(w io.Writer, summaries []hostSummary) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(summaries); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func writeStatusCSV(w io.Writer, summaries []hostSummary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(statusColumns); err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		if err := cw.Write(s.row()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeStatusMarkdown(w io.Writer, summaries []hostSummary) error {
+	fmt.Fprintln(w, "| Xname | Host | Target | Status | Version | Requested | Policy | Error |") //nolint:errcheck
+	fmt.Fprintln(w, "|---|---|---|---|---|---|---|---|")                                         //nolint:errcheck
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	for _, s := range summaries {
+		cells := s.row()
+		for i, c := range cells {
+			cells[i] = cell.Replace(c)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusExitError returns an error carrying a non-zero exit code when any target is in
+// error, is outside the version policy or, with --require-version, does not report
+// --expected-version.
+func statusExitError(summaries []hostSummary) error {
+	var failed, behind int
+	p := versionParser()
+	for _, s := range summaries {
+		if s.Status == "error" {
+			failed++
+		}
+		if s.Policy != "" || (fwRequireVersion && !version.Equal(p, s.ObservedVersion, fwExpectedVersion)) {
+			behind++
+		}
+	}
+	switch {
+	case failed > 0:
+		return &exitError{code: exitStatusError, err: fmt.Errorf("%d target(s) in error", failed)}
+	case behind > 0:
+		return &exitError{code: exitStatusVersion, err: fmt.Errorf("%d target(s) not at the required version", behind)}
+	}
+	return nil
+}
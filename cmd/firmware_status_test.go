@@ -7,12 +7,15 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -80,8 +83,8 @@ func TestFirmwareStatusDetectsFailure(t *testing.T) {
 
 	cmd := firmwareStatusCmd
 	cmd.SetContext(context.Background())
-	if err := cmd.RunE(cmd, []string{}); err != nil {
-		t.Fatalf("command failed: %v", err)
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitStatusError {
+		t.Fatalf("expected error exit code %d, got: %v", exitStatusError, err)
 	}
 
 	w.Close() //nolint:errcheck
@@ -282,8 +285,8 @@ func TestFirmwareStatusPrefersUpdateServiceHealthCritical(t *testing.T) {
 
 	cmd := firmwareStatusCmd
 	cmd.SetContext(context.Background())
-	if err := cmd.RunE(cmd, []string{}); err != nil {
-		t.Fatalf("command failed: %v", err)
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitStatusError {
+		t.Fatalf("expected error exit code %d, got: %v", exitStatusError, err)
 	}
 
 	w.Close() //nolint:errcheck
@@ -337,8 +340,8 @@ func TestFirmwareStatusDetectsInventoryHealthWarningNoConditions(t *testing.T) {
 
 	cmd := firmwareStatusCmd
 	cmd.SetContext(context.Background())
-	if err := cmd.RunE(cmd, []string{}); err != nil {
-		t.Fatalf("command failed: %v", err)
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitStatusError {
+		t.Fatalf("expected error exit code %d, got: %v", exitStatusError, err)
 	}
 
 	w.Close() //nolint:errcheck
@@ -400,8 +403,8 @@ func TestFirmwareStatusDetectsInventoryHealthCriticalWithCondition(t *testing.T)
 
 	cmd := firmwareStatusCmd
 	cmd.SetContext(context.Background())
-	if err := cmd.RunE(cmd, []string{}); err != nil {
-		t.Fatalf("command failed: %v", err)
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitStatusError {
+		t.Fatalf("expected error exit code %d, got: %v", exitStatusError, err)
 	}
 
 	w.Close() //nolint:errcheck
@@ -496,3 +499,369 @@ func TestFirmwareStatusDetectsTaskServiceRunning(t *testing.T) {
 		t.Fatalf("expected one in-progress update via TaskService, got:\n%s", output)
 	}
 }
+
+func TestFirmwareStatusDetectsStagedImage(t *testing.T) {
+	// Mock server with an image staged for OnReset (StandbyOffline) and a pending task
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/TaskService/Tasks") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Members": []map[string]any{{"@odata.id": "/redfish/v1/TaskService/Tasks/7"}},
+			})
+			return
+		}
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/TaskService/Tasks/7") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Id":        "7",
+				"Name":      "Firmware Update",
+				"TaskState": "Pending",
+			})
+			return
+		}
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/UpdateService/FirmwareInventory/BMC") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BMC",
+				"Id":        "BMC",
+				"Version":   "nc.1.9.0",
+				"Status": map[string]any{
+					"Health": "OK",
+					"State":  "StandbyOffline",
+				},
+			})
+			return
+		}
+		http.NotFound(w, r)
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	fwFile = makeInventoryFile(t, host)
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeout = 2 * time.Second
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	cmd := firmwareStatusCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	w.Close() //nolint:errcheck
+	out, _ := io.ReadAll(r)
+	output := string(out)
+
+	if !strings.Contains(output, "In-progress updates: 0") {
+		t.Fatalf("staged image should not count as in-progress, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Staged updates awaiting apply: 1") || !strings.Contains(output, "x9000c1s0b0 /redfish/v1/UpdateService/FirmwareInventory/BMC") {
+		t.Fatalf("expected staged host in output, got:\n%s", output)
+	}
+}
+
+func TestFirmwareStatusWatchExitsAtExpectedVersion(t *testing.T) {
+	// The BMC reports the new version from the third inventory read onwards
+	var reads int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/UpdateService/FirmwareInventory/BMC") {
+			version := "nc.1.0.0"
+			if atomic.AddInt32(&reads, 1) >= 3 {
+				version = "nc.1.1.0"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Version": version,
+				"Status":  map[string]any{"Health": "OK", "State": "Enabled"},
+			})
+			return
+		}
+		http.NotFound(w, r)
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	fwFile = makeInventoryFile(t, host)
+	fwHostsCSV = ""
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeout = 2 * time.Second
+	fwExpectedVersion = "nc.1.1.0"
+	fwWatch = true
+	fwWatchTimeout = 5 * time.Second
+	fwStatusInterval = 10 * time.Millisecond
+	defer func() {
+		fwWatch = false
+		fwExpectedVersion = ""
+	}()
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	cmd := firmwareStatusCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	w.Close() //nolint:errcheck
+	out, _ := io.ReadAll(r)
+	output := string(out)
+
+	if !strings.Contains(output, "(poll 3)") || !strings.Contains(output, "nc.1.0.0 -> nc.1.1.0") {
+		t.Fatalf("expected version transition on the third poll, got:\n%s", output)
+	}
+	if !strings.Contains(output, "All 1 target(s) report version nc.1.1.0") {
+		t.Fatalf("expected watch to finish at expected version, got:\n%s", output)
+	}
+}
+
+func TestFirmwareStatusWatchTimeout(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/UpdateService/FirmwareInventory/BMC") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"Version": "nc.1.0.0"}) //nolint:errcheck
+			return
+		}
+		http.NotFound(w, r)
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	fwFile = makeInventoryFile(t, strings.TrimPrefix(server.URL, "https://"))
+	fwHostsCSV = ""
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeout = 2 * time.Second
+	fwExpectedVersion = "nc.2.0.0"
+	fwWatch = true
+	fwWatchTimeout = 300 * time.Millisecond
+	fwStatusInterval = 20 * time.Millisecond
+	defer func() {
+		fwWatch = false
+		fwExpectedVersion = ""
+	}()
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	cmd := firmwareStatusCmd
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, []string{})
+	w.Close() //nolint:errcheck
+	out, _ := io.ReadAll(r)
+
+	if err == nil || !strings.Contains(err.Error(), "watch timed out") {
+		t.Fatalf("expected watch timeout error, got %v\nOutput: %s", err, out)
+	}
+	if !strings.Contains(string(out), "Firmware status summary:") {
+		t.Fatalf("expected final summary, got:\n%s", out)
+	}
+}
+
+// exitCode returns the exit code Execute would use for err (0 when err is nil).
+func exitCode(err error) int {
+	var ee *exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &ee):
+		return ee.code
+	default:
+		return 1
+	}
+}
+
+// statusFormatServer serves a healthy BMC inventory entry at the given version.
+func statusFormatServer(t *testing.T, version string) string {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/UpdateService/FirmwareInventory/BMC") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Id":      "BMC",
+				"Version": version,
+				"Status":  map[string]any{"Health": "OK", "State": "Enabled"},
+			})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "https://")
+}
+
+func TestFirmwareStatusFormats(t *testing.T) {
+	host := statusFormatServer(t, "nc.1.9.8")
+	fwFile = makeInventoryFile(t, host)
+	fwHostsCSV = ""
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeout = 2 * time.Second
+	fwExpectedVersion = ""
+	fwRequireVersion = false
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	t.Cleanup(func() { fwFormat = "" })
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"table", []string{"XNAME", "x9000c1s0b0", host, "idle", "nc.1.9.8"}},
+		{"csv", []string{"xname,host,target,status,observed_version,requested_version,policy,error\n", "x9000c1s0b0," + host + ",/redfish/v1/UpdateService/FirmwareInventory/BMC,idle,nc.1.9.8,,,\n"}},
+		{"yaml", []string{"- xname: x9000c1s0b0\n", "  observed_version: nc.1.9.8\n"}},
+		{"ndjson", []string{`{"xname":"x9000c1s0b0","host":"` + host + `"`}},
+		{"json", []string{`"xname": "x9000c1s0b0"`}},
+		{"markdown", []string{"| Xname | Host |", "| x9000c1s0b0 | " + host + " |"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			fwFormat = tt.format
+			out, err := runCmd(t, firmwareStatusCmd)
+			if err != nil {
+				t.Fatalf("command failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected %q in output, got:\n%s", want, out)
+				}
+			}
+		})
+	}
+
+	fwFormat = "xml"
+	if _, err := runCmd(t, firmwareStatusCmd); err == nil || !strings.Contains(err.Error(), "unknown --format") {
+		t.Fatalf("expected unknown format error, got: %v", err)
+	}
+}
+
+func TestFirmwareStatusRequireVersion(t *testing.T) {
+	host := statusFormatServer(t, "nc.1.9.0")
+	fwFile = makeInventoryFile(t, host)
+	fwHostsCSV = ""
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeout = 2 * time.Second
+	fwFormat = ""
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	t.Cleanup(func() { fwExpectedVersion, fwRequireVersion = "", false })
+
+	fwExpectedVersion = "nc.1.9.8"
+	fwRequireVersion = false
+	if _, err := runCmd(t, firmwareStatusCmd); err != nil {
+		t.Fatalf("without --require-version a version mismatch should not fail: %v", err)
+	}
+
+	fwRequireVersion = true
+	if _, err := runCmd(t, firmwareStatusCmd); exitCode(err) != exitStatusVersion {
+		t.Fatalf("expected exit code %d, got: %v", exitStatusVersion, err)
+	}
+
+	fwExpectedVersion = "nc.1.9.0"
+	if _, err := runCmd(t, firmwareStatusCmd); err != nil {
+		t.Fatalf("expected success when at version, got: %v", err)
+	}
+}
+
+func TestFirmwareStatusVersionPolicy(t *testing.T) {
+	host := statusFormatServer(t, "nc.1.9.0")
+	fwFile = makeInventoryFile(t, host)
+	fwHostsCSV = ""
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeout = 2 * time.Second
+	fwFormat = ""
+	fwExpectedVersion, fwRequireVersion = "", false
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	t.Cleanup(func() {
+		fwMinVersion, fwMaxVersion, fwExpectedVersion, fwRequireVersion = "", "", "", false
+		fwVersionScheme = "exact"
+	})
+
+	fwMinVersion = "1.10"
+	out, err := runCmd(t, firmwareStatusCmd)
+	if exitCode(err) != exitStatusVersion {
+		t.Fatalf("expected exit code %d, got: %v", exitStatusVersion, err)
+	}
+	if !strings.Contains(out, "Outside version policy: 1") || !strings.Contains(out, "nc.1.9.0 (below-min)") {
+		t.Fatalf("expected policy violation in output, got:\n%s", out)
+	}
+
+	fwMinVersion = "1.9"
+	fwExpectedVersion, fwRequireVersion = "1.9.0", true
+	if _, err := runCmd(t, firmwareStatusCmd); exitCode(err) != exitStatusVersion {
+		t.Fatalf("expected nc.1.9.0 not to match 1.9.0 exactly, got: %v", err)
+	}
+	fwVersionScheme = "dotted"
+	if out, err := runCmd(t, firmwareStatusCmd); err != nil {
+		t.Fatalf("expected nc.1.9.0 to satisfy the policy and match 1.9.0, got: %v\n%s", err, out)
+	}
+}
+
+func TestFirmwareStatusHistory(t *testing.T) {
+	old := statusFormatServer(t, "nc.1.9.8")
+	updated := statusFormatServer(t, "nc.1.10.0")
+	fwHostsCSV = ""
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeout = 2 * time.Second
+	fwExpectedVersion = ""
+	fwRequireVersion = false
+	fwFormat = "json"
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	fwHistory = filepath.Join(t.TempDir(), "history.db")
+	histTimeZone = time.UTC
+	t.Cleanup(func() {
+		fwFormat, fwHistory, histVersion = "", "", ""
+		histTimeZone = time.Local
+	})
+
+	// Two status runs: the host first reports the old version, then the new one
+	for _, host := range []string{old, updated} {
+		fwFile = makeInventoryFile(t, host)
+		if out, err := runCmd(t, firmwareStatusCmd); err != nil {
+			t.Fatalf("status run failed: %v\n%s", err, out)
+		}
+	}
+
+	histVersion = "nc.1.10.0"
+	out, err := runCmd(t, firmwareHistoryCmd)
+	if err != nil {
+		t.Fatalf("history failed: %v\n%s", err, out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 || !strings.HasSuffix(lines[1], "1        0          0.0%") || !strings.HasSuffix(lines[2], "1        1          100.0%") {
+		t.Fatalf("unexpected compliance table:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[3], "Fully compliant since ") {
+		t.Errorf("expected the time the version was reached, got %q", lines[3])
+	}
+}
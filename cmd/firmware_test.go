@@ -13,10 +13,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"bootstrap/internal/journal"
 )
 
 // Mock Redfish server for firmware testing
@@ -385,3 +390,616 @@ func TestDefaultTargets(t *testing.T) {
 		})
 	}
 }
+
+// rolloutTestServer serves a BMC FirmwareInventory whose version switches to "2.0" after the
+// first SimpleUpdate POST. When failPost is set, every POST fails instead.
+func rolloutTestServer(t *testing.T, failPost bool, posts *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST":
+			atomic.AddInt32(posts, 1)
+			if failPost {
+				http.Error(w, "update rejected", http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasSuffix(r.URL.Path, "/UpdateService/FirmwareInventory/BMC"):
+			version := "1.0"
+			if atomic.LoadInt32(posts) > 0 {
+				version = "2.0"
+			}
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Version": version,
+				"Status":  map[string]any{"State": "Enabled", "Health": "OK"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// configureRollout writes a three-chassis inventory pointing at server and resets firmware globals.
+func configureRollout(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	host := strings.TrimPrefix(server.URL, "https://")
+	content := fmt.Sprintf(`bmcs:
+  - xname: x9000c1s0b0
+    ip: %[1]s
+  - xname: x9000c1s0b1
+    ip: %[1]s
+  - xname: x9000c3s0b0
+    ip: %[1]s
+  - xname: x9000c5s0b0
+    ip: %[1]s
+`, host)
+	path := t.TempDir() + "/inventory.yaml"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fwFile = path
+	fwHostsCSV = ""
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwProtocol = "HTTP"
+	fwInsecure = true
+	fwTimeout = 5 * time.Second
+	fwDryRun = false
+	fwBatchSize = 0
+	fwTargets = nil
+	fwExpectedVersion = ""
+	fwForce = false
+	fwCanary = 1
+	fwWaveBy = "chassis"
+	fwFailureThreshold = ""
+	fwWaveTimeout = 5 * time.Second
+	fwWaveInterval = 10 * time.Millisecond
+	fwConfirmWaves = false
+	fwVersionScheme = "exact"
+	fwMinVersion, fwMaxVersion = "", ""
+	fwAllowDowngrade = false
+	fwPreflight, fwSHA256, fwSHA512 = false, "", ""
+	fwReboot, fwRequireOff = rebootNever, false
+	t.Cleanup(func() {
+		fwPreflight, fwSHA256, fwSHA512 = false, "", ""
+		fwReboot, fwRequireOff = rebootNever, false
+		fwMinVersion, fwMaxVersion = "", ""
+		fwAllowDowngrade = false
+		fwCanary = 0
+		fwWaveBy = "none"
+		fwFailureThreshold = ""
+		fwConfirmWaves = false
+		firmwareCmd.SetIn(nil)
+	})
+}
+
+func TestFirmwareRolloutHaltsOnFailureThreshold(t *testing.T) {
+	var posts int32
+	server := rolloutTestServer(t, true, &posts)
+	configureRollout(t, server)
+	fwFailureThreshold = "0"
+
+	output, err := runCmd(t, firmwareCmd)
+	if err == nil || !strings.Contains(err.Error(), "halting rollout after wave 1/4 (canary)") {
+		t.Fatalf("expected rollout to halt after canary, got err=%v\nOutput: %s", err, output)
+	}
+	if got := atomic.LoadInt32(&posts); got != 1 {
+		t.Fatalf("expected only the canary to be updated, got %d POSTs", got)
+	}
+}
+
+func TestFirmwareRolloutWaitsForExpectedVersion(t *testing.T) {
+	var posts int32
+	server := rolloutTestServer(t, false, &posts)
+	configureRollout(t, server)
+	fwExpectedVersion = "2.0"
+
+	output, err := runCmd(t, firmwareCmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+	}
+	// The canary moves the shared mock to 2.0, so later waves are skipped as already current.
+	if got := atomic.LoadInt32(&posts); got != 1 {
+		t.Fatalf("expected 1 POST, got %d\nOutput: %s", got, output)
+	}
+	for _, want := range []string{"Wave 1/4 (canary)", "Wave 2/4 (x9000c1)", "Wave 4/4 (x9000c5)", "Waiting for 1 host(s) to report version 2.0"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+	if got := strings.Count(output, "skipping update"); got != 3 {
+		t.Fatalf("expected 3 skipped hosts, got %d\nOutput: %s", got, output)
+	}
+}
+
+func TestFirmwareRolloutConfirmDeclined(t *testing.T) {
+	var posts int32
+	server := rolloutTestServer(t, false, &posts)
+	configureRollout(t, server)
+	fwConfirmWaves = true
+	firmwareCmd.SetIn(strings.NewReader("n\n"))
+
+	output, err := runCmd(t, firmwareCmd)
+	if err == nil || !strings.Contains(err.Error(), "rollout stopped before wave 2/4") {
+		t.Fatalf("expected rollout to stop at the prompt, got err=%v\nOutput: %s", err, output)
+	}
+	if got := atomic.LoadInt32(&posts); got != 1 {
+		t.Fatalf("expected only the canary to be updated, got %d POSTs", got)
+	}
+}
+
+func TestFirmwareVersionPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  string
+		min, max  string
+		scheme    string
+		downgrade bool
+		targets   []string
+		wantPosts int32
+		wantOut   string
+		wantErr   string
+	}{
+		{name: "downgrade refused", expected: "0.9", wantPosts: 0, wantOut: "refusing downgrade"},
+		{name: "downgrade allowed", expected: "0.9", downgrade: true, wantPosts: 1},
+		{name: "downgrade refused past an unreadable target", expected: "0.9", targets: []string{
+			"/redfish/v1/UpdateService/FirmwareInventory/Missing", "/redfish/v1/UpdateService/FirmwareInventory/BMC",
+		}, wantPosts: 0, wantOut: "refusing downgrade of /redfish/v1/UpdateService/FirmwareInventory/BMC"},
+		{name: "semantically equal", expected: "v1.0.0", scheme: "dotted", wantPosts: 0, wantOut: "already at expected version"},
+		{name: "exact by default", expected: "v1.0.0", wantPosts: 1},
+		{name: "at or above min", min: "nc.0.9", wantPosts: 0, wantOut: "at or above minimum version"},
+		{name: "below min", min: "1.1", wantPosts: 1},
+		{name: "expected above max", expected: "2.0", max: "1.5", wantErr: "violates the version policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posts int32
+			server := rolloutTestServer(t, false, &posts)
+			configureRollout(t, server)
+			fwHostsCSV = strings.TrimPrefix(server.URL, "https://")
+			fwCanary, fwWaveBy = 0, "none"
+			fwExpectedVersion = tt.expected
+			fwMinVersion, fwMaxVersion = tt.min, tt.max
+			fwAllowDowngrade = tt.downgrade
+			fwTargets = tt.targets
+			if tt.scheme != "" {
+				fwVersionScheme = tt.scheme
+			}
+
+			output, err := runCmd(t, firmwareCmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+			}
+			if got := atomic.LoadInt32(&posts); got != tt.wantPosts {
+				t.Fatalf("expected %d POSTs, got %d\nOutput: %s", tt.wantPosts, got, output)
+			}
+			if !strings.Contains(output, tt.wantOut) {
+				t.Fatalf("expected %q in output:\n%s", tt.wantOut, output)
+			}
+		})
+	}
+}
+
+func TestFirmwarePreflightAbortsBeforeBMC(t *testing.T) {
+	var posts int32
+	server := rolloutTestServer(t, false, &posts)
+	configureRollout(t, server)
+	fwCanary, fwWaveBy = 0, "none"
+	images := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "bmc.bin", time.Time{}, strings.NewReader("image"))
+	}))
+	defer images.Close()
+	fwImageURI = images.URL + "/bmc-2.0.bin"
+
+	fwSHA256 = strings.Repeat("0", 64)
+	output, err := runCmd(t, firmwareCmd)
+	if err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("expected digest mismatch, got err=%v\nOutput: %s", err, output)
+	}
+	if got := atomic.LoadInt32(&posts); got != 0 {
+		t.Fatalf("expected no BMC to be contacted, got %d POSTs", got)
+	}
+
+	// sha256("image")
+	fwSHA256 = "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d"
+	output, err = runCmd(t, firmwareCmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Preflight: "+fwImageURI+": 5 bytes, sha256 verified") {
+		t.Fatalf("expected preflight summary in output:\n%s", output)
+	}
+	if got := atomic.LoadInt32(&posts); got == 0 {
+		t.Fatal("expected the update to proceed after a passing preflight")
+	}
+}
+
+func TestFirmwareResumeFromJournal(t *testing.T) {
+	var postsA, postsB int32
+	serverA := rolloutTestServer(t, false, &postsA)
+	serverB := rolloutTestServer(t, false, &postsB)
+	configureRollout(t, serverA)
+	hostA := strings.TrimPrefix(serverA.URL, "https://")
+	hostB := strings.TrimPrefix(serverB.URL, "https://")
+	fwHostsCSV = hostA + "," + hostB
+	fwCanary, fwWaveBy = 0, "none"
+
+	// A finished in the interrupted run; B was started but never finished
+	fwJournal = filepath.Join(t.TempDir(), "journal.jsonl")
+	journalLines := fmt.Sprintf(`{"time":"2026-01-01T00:00:00Z","event":"start","host":%[1]q,"image":%[3]q}
+{"time":"2026-01-01T00:00:05Z","event":"finish","host":%[1]q,"image":%[3]q,"result":"triggered"}
+{"time":"2026-01-01T00:00:06Z","event":"start","host":%[2]q,"image":%[3]q}
+`, hostA, hostB, fwImageURI)
+	if err := os.WriteFile(fwJournal, []byte(journalLines), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fwResume = true
+	t.Cleanup(func() { fwJournal, fwResume = "", false })
+
+	output, err := runCmd(t, firmwareCmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+	}
+	if atomic.LoadInt32(&postsA) != 0 || atomic.LoadInt32(&postsB) != 1 {
+		t.Fatalf("expected only the in-flight host to be updated, got A=%d B=%d\nOutput: %s", postsA, postsB, output)
+	}
+	if !strings.Contains(output, hostA+": already triggered according to the journal") {
+		t.Fatalf("expected completed host to be skipped:\n%s", output)
+	}
+
+	report, err := runCmd(t, firmwareReportCmd)
+	if err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+	if !strings.Contains(report, "Hosts: 2 (2 triggered, 0 skipped, 0 failed, 0 in-flight)") {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+	if !strings.Contains(report, "2.0") {
+		t.Fatalf("expected observed version in report:\n%s", report)
+	}
+}
+
+// verifyTestServer serves version 1.0 until SimpleUpdate is posted and then answers
+// FirmwareInventory reads with after(n), where n counts reads since the POST.
+func verifyTestServer(t *testing.T, after func(n int32) (string, int)) *httptest.Server {
+	t.Helper()
+	var posted atomic.Bool
+	var reads int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST":
+			posted.Store(true)
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasSuffix(r.URL.Path, "/UpdateService/FirmwareInventory/BMC"):
+			version, code := "1.0", http.StatusOK
+			if posted.Load() {
+				version, code = after(atomic.AddInt32(&reads, 1))
+			}
+			if code != http.StatusOK {
+				w.WriteHeader(code)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Version": version,
+				"Status":  map[string]any{"State": "Enabled", "Health": "OK"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFirmwareVerifyClassifiesHosts(t *testing.T) {
+	tests := []struct {
+		name  string
+		after func(n int32) (string, int)
+		want  string
+	}{
+		{"updated", func(int32) (string, int) { return "2.0", http.StatusOK }, "Verified %s: updated (/redfish/v1/UpdateService/FirmwareInventory/BMC: 1.0 -> 2.0)"},
+		{"unchanged", func(int32) (string, int) { return "1.0", http.StatusOK }, "WARN: %s: verification: unchanged"},
+		{"rolled back", func(n int32) (string, int) {
+			// The BMC restarts after the post-update check in SimpleUpdate and comes back on the old image
+			if n == 2 || n == 3 {
+				return "", http.StatusServiceUnavailable
+			}
+			return "1.0", http.StatusOK
+		}, "WARN: %s: verification: rolled-back"},
+		{"unreachable", func(int32) (string, int) { return "", http.StatusServiceUnavailable }, "WARN: %s: verification: unreachable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := verifyTestServer(t, tt.after)
+			configureRollout(t, server)
+			host := strings.TrimPrefix(server.URL, "https://")
+			fwHostsCSV = host
+			fwCanary, fwWaveBy = 0, "none"
+			fwExpectedVersion = "2.0"
+			fwVerify, fwVerifyTimeout, fwVerifyInterval = true, 300*time.Millisecond, 10*time.Millisecond
+			t.Cleanup(func() { fwVerify = false })
+
+			output, err := runCmd(t, firmwareCmd)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+			}
+			if want := fmt.Sprintf(tt.want, host); !strings.Contains(output, want) {
+				t.Fatalf("expected %q in output:\n%s", want, output)
+			}
+		})
+	}
+}
+
+func TestVerifyUpdateUnknownBefore(t *testing.T) {
+	server := verifyTestServer(t, nil)
+	configureRollout(t, server)
+	host := strings.TrimPrefix(server.URL, "https://")
+	target := "/redfish/v1/UpdateService/FirmwareInventory/BMC"
+
+	// Without a version from before the update any read would look like a change
+	u := fwUpdate{Targets: []string{target}}
+	if class, detail := verifyUpdate(context.Background(), host, "u", "p", u, nil, time.Second, 10*time.Millisecond); class != journal.ResultUnchanged {
+		t.Errorf("got %s (%s), want unchanged", class, detail)
+	}
+	u.ExpectedVersion = "1.0"
+	if class, detail := verifyUpdate(context.Background(), host, "u", "p", u, nil, time.Second, 10*time.Millisecond); class != journal.ResultUpdated {
+		t.Errorf("with --expected-version: got %s (%s), want updated", class, detail)
+	}
+}
+
+// powerTestServer serves one ComputerSystem that starts in power state initial and follows
+// ComputerSystem.Reset requests, except that it stays off after a restart when stuck is set.
+// The ResetTypes received and the power state at the time of the SimpleUpdate are recorded.
+// SimpleUpdate starts task 1, which reports each of taskStates on successive polls (the last
+// one repeating, Completed when there are none); a reset while the task is still Running
+// fails the test.
+func powerTestServer(t *testing.T, initial string, stuck bool, taskStates []string, resets *[]string, stateAtUpdate *string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	state := initial
+	taskState := ""
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/redfish/v1/Systems":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"}]}`))
+		case r.URL.Path == "/redfish/v1/Systems/Node0":
+			progress := "None"
+			if state == "On" {
+				progress = "OSRunning"
+			}
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Id":           "Node0",
+				"PowerState":   state,
+				"BootProgress": map[string]any{"LastState": progress},
+			})
+		case r.URL.Path == "/redfish/v1/TaskService/Tasks/1":
+			switch len(taskStates) {
+			case 0:
+				taskState = "Completed"
+			case 1:
+				taskState = taskStates[0]
+			default:
+				taskState, taskStates = taskStates[0], taskStates[1:]
+			}
+			json.NewEncoder(w).Encode(map[string]any{"TaskState": taskState}) //nolint:errcheck
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/ComputerSystem.Reset"):
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if taskState == "Running" {
+				t.Errorf("%s reset while the update task is still Running", body["ResetType"])
+			}
+			*resets = append(*resets, body["ResetType"])
+			switch body["ResetType"] {
+			case "GracefulShutdown":
+				state = "Off"
+			case "On":
+				state = "On"
+			case "GracefulRestart":
+				state = "Off"
+				if !stuck {
+					go func() {
+						time.Sleep(50 * time.Millisecond)
+						mu.Lock()
+						state = "On"
+						mu.Unlock()
+					}()
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST":
+			*stateAtUpdate = state
+			taskState = "Running"
+			w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/1")
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasSuffix(r.URL.Path, ".BIOS"):
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Version": "1.0",
+				"Status":  map[string]any{"State": "Enabled", "Health": "OK"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFirmwarePowerGating(t *testing.T) {
+	tests := []struct {
+		name        string
+		initial     string
+		stuck       bool
+		taskStates  []string
+		reboot      string
+		requireOff  bool
+		wantResets  []string
+		wantAtPost  string
+		wantWarning string
+	}{
+		{name: "require off refuses", initial: "On", reboot: rebootNever, requireOff: true,
+			wantWarning: "WARN: %s: power: --require-off: not powered off: /redfish/v1/Systems/Node0"},
+		{name: "require off passes", initial: "Off", reboot: rebootNever, requireOff: true, wantAtPost: "Off"},
+		{name: "required powers off and back on", initial: "On", reboot: rebootRequired,
+			wantResets: []string{"GracefulShutdown", "On"}, wantAtPost: "Off"},
+		{name: "required leaves off node off", initial: "Off", reboot: rebootRequired, wantAtPost: "Off"},
+		{name: "after restarts", initial: "On", reboot: rebootAfter,
+			wantResets: []string{"GracefulRestart"}, wantAtPost: "On"},
+		{name: "after reports node not back", initial: "On", stuck: true, reboot: rebootAfter,
+			wantResets: []string{"GracefulRestart"}, wantAtPost: "On",
+			wantWarning: "WARN: %s: power: did not come back: /redfish/v1/Systems/Node0"},
+		{name: "after waits for the update task", initial: "On", reboot: rebootAfter,
+			taskStates: []string{"Running", "Running", "Running", "Completed"},
+			wantResets: []string{"GracefulRestart"}, wantAtPost: "On"},
+		{name: "required powers back on after a failed task", initial: "On", reboot: rebootRequired,
+			taskStates: []string{"Running", "Exception"},
+			wantResets: []string{"GracefulShutdown", "On"}, wantAtPost: "Off",
+			wantWarning: "WARN: %s: update task: update task failed: Exception"},
+		{name: "required leaves nodes off while the task runs", initial: "On", reboot: rebootRequired,
+			taskStates: []string{"Running"},
+			wantResets: []string{"GracefulShutdown"}, wantAtPost: "Off",
+			wantWarning: "WARN: %s: update task: context deadline exceeded (last: Running); node power left as it is"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resets []string
+			var atPost string
+			server := powerTestServer(t, tt.initial, tt.stuck, tt.taskStates, &resets, &atPost)
+			configureRollout(t, server)
+			host := strings.TrimPrefix(server.URL, "https://")
+			fwHostsCSV = host
+			fwCanary, fwWaveBy = 0, "none"
+			fwType = "bios"
+			fwReboot, fwRequireOff = tt.reboot, tt.requireOff
+			fwPowerTimeout, fwPowerInterval = 300*time.Millisecond, 10*time.Millisecond
+			fwTaskTimeout = 200 * time.Millisecond
+
+			output, err := runCmd(t, firmwareCmd)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+			}
+			if !slices.Equal(resets, tt.wantResets) {
+				t.Errorf("resets = %v, want %v", resets, tt.wantResets)
+			}
+			if atPost != tt.wantAtPost {
+				t.Errorf("power state at SimpleUpdate = %q, want %q", atPost, tt.wantAtPost)
+			}
+			if tt.wantWarning != "" {
+				if want := fmt.Sprintf(tt.wantWarning, host); !strings.Contains(output, want) {
+					t.Fatalf("expected %q in output:\n%s", want, output)
+				}
+			} else if strings.Contains(output, "WARN") {
+				t.Fatalf("unexpected warning:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestFirmwarePowerFlagValidation(t *testing.T) {
+	server := powerTestServer(t, "On", false, nil, new([]string), new(string))
+	configureRollout(t, server)
+	fwReboot = "sometimes"
+	if _, err := runCmd(t, firmwareCmd); err == nil || !strings.Contains(err.Error(), `invalid --reboot "sometimes"`) {
+		t.Fatalf("expected invalid --reboot error, got %v", err)
+	}
+	fwReboot, fwRequireOff = rebootAfter, true
+	if _, err := runCmd(t, firmwareCmd); err == nil || !strings.Contains(err.Error(), "--require-off cannot be combined") {
+		t.Fatalf("expected combination error, got %v", err)
+	}
+}
+
+// catalogTestServer reports a Manager and Chassis with the given model and records the
+// ImageURI of each SimpleUpdate.
+func catalogTestServer(t *testing.T, model string, posted *[]string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/redfish/v1/Managers":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`))
+		case r.URL.Path == "/redfish/v1/Managers/BMC":
+			_, _ = w.Write([]byte(`{"Id":"BMC","Manufacturer":"HPE","Model":"Windom NC"}`))
+		case r.URL.Path == "/redfish/v1/Chassis":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Chassis/Blade0"}]}`))
+		case r.URL.Path == "/redfish/v1/Chassis/Blade0":
+			json.NewEncoder(w).Encode(map[string]any{"Manufacturer": "HPE", "Model": model}) //nolint:errcheck
+		case r.Method == "POST":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			*posted = append(*posted, fmt.Sprint(body["ImageURI"]))
+			mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFirmwareCatalogPicksImageByModel(t *testing.T) {
+	var ex425Posts, otherPosts []string
+	ex425 := catalogTestServer(t, "EX425 Compute Blade", &ex425Posts)
+	other := catalogTestServer(t, "EX9000", &otherPosts)
+	configureRollout(t, ex425)
+	ex425Host := strings.TrimPrefix(ex425.URL, "https://")
+	otherHost := strings.TrimPrefix(other.URL, "https://")
+	fwHostsCSV = ex425Host + "," + otherHost
+	fwCanary, fwWaveBy = 0, "none"
+	fwType, fwImageURI = "nc", ""
+	fwCatalog = filepath.Join(t.TempDir(), "catalog.yaml")
+	fwCatalogBaseURL = "http://10.0.0.1/images"
+	t.Cleanup(func() { fwCatalog, fwCatalogBaseURL = "", "" })
+	if err := os.WriteFile(fwCatalog, []byte(`images:
+  - name: nc-ex425-1.9.8
+    component: nc
+    version: nc.1.9.8
+    manufacturer: HPE
+    models: ["EX425*"]
+    path: nc-ex425-1.9.8.bin
+  - name: nc-ex235-1.9.8
+    component: nc
+    version: nc.1.9.8
+    manufacturer: HPE
+    models: ["EX235*"]
+    path: nc-ex235-1.9.8.bin
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := runCmd(t, firmwareCmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+	}
+	if !slices.Equal(ex425Posts, []string{"http://10.0.0.1/images/nc-ex425-1.9.8.bin"}) {
+		t.Errorf("EX425 host got updates %v", ex425Posts)
+	}
+	if len(otherPosts) != 0 {
+		t.Errorf("host without a compatible image was updated: %v", otherPosts)
+	}
+	for _, want := range []string{
+		ex425Host + ": catalog image nc-ex425-1.9.8 (nc.1.9.8)",
+		"WARN: " + otherHost + ": not updated: no compatible image in catalog for nc HPE Windom NC, HPE EX9000",
+		"No compatible catalog image: 1 host(s)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
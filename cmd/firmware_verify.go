@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"bootstrap/internal/journal"
+	"bootstrap/internal/redfish"
+	"bootstrap/internal/version"
+)
+
+var (
+	fwVerify         bool
+	fwVerifyTimeout  time.Duration
+	fwVerifyInterval time.Duration
+)
+
+// targetVersions reads the current version of each target on host. Targets that cannot be
+// read are left out.
+func targetVersions(ctx context.Context, host, user, pass string, targets []string) map[string]string {
+	out := make(map[string]string, len(targets))
+	for _, target := range targets {
+		if inv, err := redfish.GetFirmwareInventory(ctx, host, user, pass, fwInsecure, fwTimeout, target); err == nil {
+			out[target] = inv.Version
+		}
+	}
+	return out
+}
+
+// verifyTarget tracks one target while waiting for its version to change.
+type verifyTarget struct {
+	before    string
+	latest    string
+	reachable bool // the most recent read succeeded
+	changed   bool // some read reported a version other than before
+	rebooted  bool // some read failed, as while the BMC restarts
+	done      bool
+	unknown   bool // before is unknown and there is no expected version, so a change cannot be told
+}
+
+// verifyUpdate polls the targets of u on host every interval until each reports a version
+// that differs from before and matches --expected-version (when set), or until timeout
+// passes. Read errors while the BMC reboots are expected and only count
+// against the host if they persist. Each target is classified as updated, unreachable (the
+// last read failed), rolled-back (back on the old version after the new one was seen or the
+// BMC restarted, or on an older version) or unchanged; a target whose version before the
+// update is unknown is only updated once it reports --expected-version, and unchanged when
+// there is none. The host gets its worst target's class, and detail lists the version
+// transitions.
+func verifyUpdate(parent context.Context, host, user, pass string, u fwUpdate, before map[string]string, timeout, interval time.Duration) (class, detail string) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	p := versionParser()
+	targets := make(map[string]*verifyTarget, len(u.Targets))
+	for _, t := range u.Targets {
+		targets[t] = &verifyTarget{before: before[t]}
+	}
+poll:
+	for {
+		pending := 0
+		for _, t := range u.Targets {
+			vt := targets[t]
+			if vt.done || vt.unknown {
+				continue
+			}
+			inv, err := redfish.GetFirmwareInventory(ctx, host, user, pass, fwInsecure, fwTimeout, t)
+			if ctx.Err() != nil {
+				// A read cut short by the deadline says nothing about the BMC
+				break
+			}
+			vt.reachable = err == nil
+			vt.rebooted = vt.rebooted || err != nil
+			if err == nil {
+				vt.latest = inv.Version
+				switch {
+				case vt.before == "" && u.ExpectedVersion == "":
+					vt.unknown = true
+				case vt.before == "" || !version.Equal(p, inv.Version, vt.before):
+					vt.changed = true
+					vt.done = u.ExpectedVersion == "" || version.Equal(p, inv.Version, u.ExpectedVersion)
+				}
+			}
+			if !vt.done && !vt.unknown {
+				pending++
+			}
+		}
+		if pending == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break poll
+		case <-time.After(interval):
+		}
+	}
+
+	class = journal.ResultUpdated
+	rank := map[string]int{journal.ResultUpdated: 0, journal.ResultUnchanged: 1, journal.ResultRolledBack: 2, journal.ResultUnreachable: 3}
+	var details []string
+	for _, t := range u.Targets {
+		vt := targets[t]
+		c := journal.ResultUpdated
+		switch {
+		case vt.done:
+		case vt.unknown:
+			c = journal.ResultUnchanged
+		case !vt.reachable:
+			c = journal.ResultUnreachable
+		case (vt.changed || vt.rebooted) && vt.before != "" && version.Equal(p, vt.latest, vt.before):
+			// The new image was seen, or the BMC restarted, but it came back on the old one
+			c = journal.ResultRolledBack
+		default:
+			// Still on the old version, or on one other than --expected-version; only an
+			// older version counts as a rollback
+			c = journal.ResultUnchanged
+			if cmp, ok := version.CompareStrings(p, vt.latest, vt.before); ok && cmp < 0 {
+				c = journal.ResultRolledBack
+			}
+		}
+		if rank[c] > rank[class] {
+			class = c
+		}
+		before := vt.before
+		if before == "" {
+			before = "(unknown)"
+		}
+		latest := vt.latest
+		if !vt.reachable {
+			latest = "(unreachable)"
+		}
+		details = append(details, fmt.Sprintf("%s: %s -> %s", t, before, latest))
+	}
+	return class, strings.Join(details, ", ")
+}
+
+func init() {
+	firmwareCmd.Flags().BoolVar(&fwVerify, "verify", false, "after triggering, wait until each target's version changes (and matches --expected-version) and classify hosts as updated, unchanged, rolled-back or unreachable")
+	firmwareCmd.Flags().DurationVar(&fwVerifyTimeout, "verify-timeout", 20*time.Minute, "maximum time to wait per host for --verify, including BMC reboots")
+	firmwareCmd.Flags().DurationVar(&fwVerifyInterval, "verify-interval", 15*time.Second, "poll interval for --verify")
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/version"
+)
+
+var (
+	fwWatch        bool
+	fwWatchTimeout time.Duration
+)
+
+const (
+	ansiClear = "\033[H\033[2J"
+	ansiBold  = "\033[1m"
+	ansiReset = "\033[0m"
+)
+
+// isTerminal reports whether f is attached to a terminal, so the watch table can be
+// redrawn in place and transitions highlighted.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// watchFirmwareStatus polls every --interval and redraws the status table until all targets
+// report --expected-version, --watch-timeout passes, or the user interrupts.
+func watchFirmwareStatus(parent context.Context, hosts []bmcHost, targets []string, user, pass string) error {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if fwWatchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, fwWatchTimeout)
+		defer cancel()
+	}
+
+	tty := isTerminal(os.Stdout)
+	prev := map[string]hostSummary{}
+	var last []hostSummary
+	for poll := 1; ctx.Err() == nil; poll++ {
+		summaries := collectFirmwareStatus(ctx, hosts, targets, user, pass)
+		if ctx.Err() != nil && last != nil {
+			// A poll cut short by Ctrl-C or the timeout is incomplete; keep the previous one
+			break
+		}
+		sortSummaries(summaries)
+		if tty {
+			fmt.Print(ansiClear)
+		}
+		renderWatchTable(os.Stdout, poll, summaries, prev, tty)
+		last = summaries
+		prev = map[string]hostSummary{}
+		for _, s := range summaries {
+			prev[s.Host+" "+s.Target] = s
+		}
+
+		if fwExpectedVersion != "" && allAtVersion(summaries, fwExpectedVersion) {
+			fmt.Printf("All %d target(s) report version %s\n", len(summaries), fwExpectedVersion)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(fwStatusInterval):
+		}
+	}
+
+	fmt.Println()
+	printStatusSummary(len(hosts), last)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("watch timed out after %s", fwWatchTimeout)
+	}
+	return nil
+}
+
+// renderWatchTable writes one frame of the watch table. Rows that changed status or version
+// since the previous poll are marked with '*' and, on a terminal, shown in bold.
+func renderWatchTable(w io.Writer, poll int, summaries []hostSummary, prev map[string]hostSummary, tty bool) {
+	counts := map[string]int{}
+	for _, s := range summaries {
+		counts[s.Status]++
+	}
+	fmt.Fprintf(w, "Firmware status at %s (poll %d): %d in-progress, %d staged, %d error, %d idle\n", //nolint:errcheck
+		time.Now().Format("15:04:05"), poll, counts["in-progress"], counts["staged"], counts["error"], counts["idle"])
+
+	// On a terminal every row starts with an escape sequence of the same length so that
+	// highlighting does not disturb column alignment.
+	lead := func(bold bool) string {
+		switch {
+		case !tty:
+			return ""
+		case bold:
+			return ansiBold
+		default:
+			return ansiReset
+		}
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s \tNAME\tTARGET\tSTATUS\tVERSION\tCHANGE\n", lead(false)) //nolint:errcheck
+	for _, s := range summaries {
+		change := ""
+		if p, ok := prev[s.Host+" "+s.Target]; ok {
+			switch {
+			case p.Status != s.Status && p.ObservedVersion != s.ObservedVersion:
+				change = fmt.Sprintf("%s -> %s, %s -> %s", p.Status, s.Status, p.ObservedVersion, s.ObservedVersion)
+			case p.Status != s.Status:
+				change = fmt.Sprintf("%s -> %s", p.Status, s.Status)
+			case p.ObservedVersion != s.ObservedVersion:
+				change = fmt.Sprintf("%s -> %s", p.ObservedVersion, s.ObservedVersion)
+			}
+		}
+		mark, tail := " ", ""
+		if change != "" {
+			mark = "*"
+			if tty {
+				tail = ansiReset
+			}
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\t%s%s\n", lead(change != ""), mark, //nolint:errcheck
+			s.label(), s.Target, s.Status, s.ObservedVersion, change, tail)
+	}
+	tw.Flush() //nolint:errcheck
+}
+
+// allAtVersion reports whether every summary observed the given version under --version-scheme.
+func allAtVersion(summaries []hostSummary, want string) bool {
+	if len(summaries) == 0 {
+		return false
+	}
+	for _, s := range summaries {
+		if !version.Equal(versionParser(), s.ObservedVersion, want) {
+			return false
+		}
+	}
+	return true
+}
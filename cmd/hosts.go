@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"bootstrap/internal/fanout"
+)
+
+// bmcHost pairs the address used to reach a BMC with its xname.
+// Xname is empty for hosts given directly with --hosts.
+type bmcHost struct {
+	Xname string
+	Host  string
+}
+
+// loadBMCHosts resolves the BMCs to contact from a comma-separated host list or,
+// when that is empty, from bmcs[] in the inventory file.
+func loadBMCHosts(file, hostsCSV string) ([]bmcHost, error) {
+	var hosts []bmcHost
+	if strings.TrimSpace(hostsCSV) != "" {
+		for _, h := range strings.Split(hostsCSV, ",") {
+			h = strings.TrimSpace(h)
+			if h != "" {
+				hosts = append(hosts, bmcHost{Host: h})
+			}
+		}
+		return hosts, nil
+	}
+	if file == "" {
+		return nil, errors.New("at least one of --file or --hosts is required")
+	}
+	doc, err := loadInventory(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.BMCs) == 0 {
+		return nil, fmt.Errorf("input must contain non-empty bmcs[]")
+	}
+	for _, b := range doc.BMCs {
+		host := b.IP
+		if host == "" {
+			host = b.Xname
+		}
+		hosts = append(hosts, bmcHost{Xname: b.Xname, Host: host})
+	}
+	return hosts, nil
+}
+
+// label returns the xname when known, otherwise the host address.
+func (b bmcHost) label() string {
+	if b.Xname != "" {
+		return b.Xname
+	}
+	return b.Host
+}
+
+// runHosts calls fn for every host concurrently within limits, scheduling round-robin across
+// chassis (see fanout.Run), and returns when all calls have returned.
+func runHosts(hosts []bmcHost, limits fanout.Limits, fn func(i int, h bmcHost)) {
+	xnames := make([]string, len(hosts))
+	for i, h := range hosts {
+		xnames[i] = h.Xname
+	}
+	fanout.Run(xnames, limits, func(i int) { fn(i, hosts[i]) })
+}
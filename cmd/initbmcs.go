@@ -12,7 +12,6 @@ import (
 	"bootstrap/internal/inventory"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -39,19 +38,18 @@ var initBmcsCmd = &cobra.Command{
 		if len(chassis) == 0 {
 			return fmt.Errorf("--chassis must specify at least one entry, e.g. x9000c1=02:23:28:01")
 		}
-		bmcs, err := initbmcs.Generate(chassis, initNodesPerChas, initNodesPerBMC, initStartNID, initBMCSubnet, initStartIP)
+		doc, err := initbmcs.GenerateInventory(chassis, initNodesPerChas, initNodesPerBMC, initStartNID, initBMCSubnet, initStartIP)
 		if err != nil {
 			return err
 		}
-		doc := inventory.FileFormat{BMCs: bmcs, Nodes: nil}
-		bytes, err := yaml.Marshal(&doc)
+		bytes, err := inventory.Marshal(&doc)
 		if err != nil {
 			return err
 		}
 		if err := os.WriteFile(initFile, bytes, 0o644); err != nil {
 			return err
 		}
-		fmt.Printf("Wrote initial BMC inventory to %s with %d entries\n", initFile, len(bmcs))
+		fmt.Printf("Wrote initial BMC inventory to %s with %d entries and %d node placeholder(s)\n", initFile, len(doc.BMCs), len(doc.Nodes))
 		return nil
 	},
 }
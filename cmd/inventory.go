@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"bootstrap/internal/inventory"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+var (
+	invFile         string
+	invDryRun       bool
+	invBackupSuffix string
+	invBMCSubnet    string
+	invNodeSubnet   string
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Maintain inventory YAML files",
+}
+
+var inventoryMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite an inventory file in the latest schema, keeping a backup",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if invFile == "" {
+			return errors.New("--file is required")
+		}
+		raw, err := os.ReadFile(invFile)
+		if err != nil {
+			return err
+		}
+		doc, from, err := inventory.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("inventory %s: %w", invFile, err)
+		}
+		out, err := inventory.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		out = append(append([]byte{}, leadingComments(raw)...), out...)
+		if bytes.Equal(raw, out) {
+			fmt.Printf("%s is already at %s\n", invFile, inventory.APIVersion)
+			return nil
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(raw)),
+			B:        difflib.SplitLines(string(out)),
+			FromFile: invFile + " (" + from + ")",
+			ToFile:   invFile + " (" + inventory.APIVersion + ")",
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Print(diff)
+		if invDryRun {
+			fmt.Printf("[dry-run] would migrate %s from %s to %s\n", invFile, from, inventory.APIVersion)
+			return nil
+		}
+		if invBackupSuffix != "" {
+			if err := os.WriteFile(invFile+invBackupSuffix, raw, 0o644); err != nil {
+				return fmt.Errorf("backup: %w", err)
+			}
+		}
+		if err := os.WriteFile(invFile, out, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Migrated %s from %s to %s", invFile, from, inventory.APIVersion)
+		if invBackupSuffix != "" {
+			fmt.Printf(" (backup in %s)", invFile+invBackupSuffix)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+var inventoryValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check an inventory file for duplicate, malformed and inconsistent entries",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if invFile == "" {
+			return errors.New("--file is required")
+		}
+		raw, err := os.ReadFile(invFile)
+		if err != nil {
+			return err
+		}
+		problems, err := inventory.Validate(raw, inventory.ValidateOptions{BMCSubnet: invBMCSubnet, NodeSubnet: invNodeSubnet})
+		if err != nil {
+			return fmt.Errorf("inventory %s: %w", invFile, err)
+		}
+		errs := printProblems(os.Stdout, invFile, problems)
+		fmt.Printf("%s: %d error(s), %d warning(s)\n", invFile, errs, len(problems)-errs)
+		if errs > 0 {
+			// The problems are already listed; usage would only bury them
+			cmd.SilenceUsage = true
+			return fmt.Errorf("inventory %s has %d error(s)", invFile, errs)
+		}
+		return nil
+	},
+}
+
+// loadInventory reads and validates an inventory file before a command acts on it,
+// printing any problems to stderr. Files with validation errors are rejected.
+func loadInventory(file string) (*inventory.FileFormat, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	problems, err := inventory.Validate(raw, inventory.ValidateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("inventory %s: %w", file, err)
+	}
+	if errs := printProblems(os.Stderr, file, problems); errs > 0 {
+		return nil, fmt.Errorf("inventory %s has %d error(s); see `inventory validate`", file, errs)
+	}
+	doc, _, err := inventory.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("inventory %s: %w", file, err)
+	}
+	return doc, nil
+}
+
+// printProblems writes one FILE:LINE line per problem and returns the number of errors.
+func printProblems(w io.Writer, file string, problems []inventory.Problem) int {
+	errs := 0
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s:%s\n", file, p) //nolint:errcheck
+		if p.Severity == inventory.SeverityError {
+			errs++
+		}
+	}
+	return errs
+}
+
+// leadingComments returns the comment and blank lines at the top of a YAML file, such as a
+// license header, which re-encoding the document would otherwise drop.
+func leadingComments(raw []byte) []byte {
+	n := 0
+	for rest := raw; len(rest) > 0; {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line = rest[:i+1]
+		}
+		if t := bytes.TrimSpace(line); len(t) > 0 && t[0] != '#' {
+			break
+		}
+		n += len(line)
+		rest = rest[len(line):]
+	}
+	return raw[:n]
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+	inventoryCmd.PersistentFlags().StringVarP(&invFile, "file", "f", "", "inventory YAML file (required)")
+	inventoryCmd.AddCommand(inventoryMigrateCmd)
+	inventoryMigrateCmd.Flags().BoolVar(&invDryRun, "dry-run", false, "print the diff without rewriting the file")
+	inventoryMigrateCmd.Flags().StringVar(&invBackupSuffix, "backup-suffix", ".bak", "suffix of the copy of the original file kept next to it (empty disables the backup)")
+	inventoryCmd.AddCommand(inventoryValidateCmd)
+	inventoryValidateCmd.Flags().StringVar(&invBMCSubnet, "bmc-subnet", "", "CIDR that every bmcs[] IP must be in (optional)")
+	inventoryValidateCmd.Flags().StringVar(&invNodeSubnet, "node-subnet", "", "CIDR that every nodes[] IP must be in (optional)")
+}
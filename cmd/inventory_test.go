@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInventoryMigrate(t *testing.T) {
+	legacy := "# SPDX-License-Identifier: MIT\n\nbmcs:\n    - xname: x9000c1s0b0\n      ip: 192.168.100.1\nnodes:\n    - xname: x9000c1s0b0n0\n      ip: 10.42.0.1\n"
+	file := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(file, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invFile, invDryRun, invBackupSuffix = file, true, ".bak"
+
+	out, err := runCmd(t, inventoryMigrateCmd)
+	if err != nil {
+		t.Fatalf("dry-run: %v\n%s", err, out)
+	}
+	for _, want := range []string{"+apiVersion: bootstrap.openchami.io/v1", "+kind: Inventory", "+      bmc: x9000c1s0b0", "[dry-run] would migrate"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diff missing %q:\n%s", want, out)
+		}
+	}
+	if raw, _ := os.ReadFile(file); string(raw) != legacy {
+		t.Fatal("dry-run rewrote the file")
+	}
+
+	invDryRun = false
+	if out, err = runCmd(t, inventoryMigrateCmd); err != nil {
+		t.Fatalf("migrate: %v\n%s", err, out)
+	}
+	if backup, _ := os.ReadFile(file + ".bak"); string(backup) != legacy {
+		t.Errorf("backup does not hold the original file: %q", backup)
+	}
+	if raw, _ := os.ReadFile(file); !strings.HasPrefix(string(raw), "# SPDX-License-Identifier: MIT\n\napiVersion: bootstrap.openchami.io/v1\n") {
+		t.Errorf("file not migrated:\n%s", raw)
+	}
+
+	if out, err = runCmd(t, inventoryMigrateCmd); err != nil || !strings.Contains(out, "already at") {
+		t.Fatalf("second migrate: err=%v\n%s", err, out)
+	}
+}
+
+func TestInventoryValidate(t *testing.T) {
+	src := "bmcs:\n  - xname: x9000c1s0b0\n    ip: 192.168.100.1\n  - xname: x9000c1s0b1\n    ip: 192.168.100.1\nnodes:\n  - xname: x9000c1s0b0n0\n    ip: 10.1.0.5\n"
+	file := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invFile, invBMCSubnet, invNodeSubnet = file, "192.168.100.0/24", "10.42.0.0/24"
+	t.Cleanup(func() { inventoryValidateCmd.SilenceUsage = false })
+	out, err := runCmd(t, inventoryValidateCmd)
+	if err == nil || !strings.Contains(err.Error(), "2 error(s)") {
+		t.Fatalf("expected validation to fail, got err=%v\n%s", err, out)
+	}
+	if !inventoryValidateCmd.SilenceUsage {
+		t.Error("usage would be printed after the problems")
+	}
+	for _, want := range []string{
+		file + ":5: error: bmcs[1].ip: duplicate IP 192.168.100.1 (also bmcs[0].ip, line 3)",
+		file + ":8: error: nodes[0].ip: 10.1.0.5 is outside 10.42.0.0/24",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Commands that act on the file refuse it too
+	if _, err := loadBMCHosts(file, ""); err == nil || !strings.Contains(err.Error(), "1 error(s)") {
+		t.Fatalf("loadBMCHosts accepted an invalid inventory: %v", err)
+	}
+}
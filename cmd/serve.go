@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"bootstrap/internal/boot"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	srvFile     string
+	srvConfig   string
+	srvListen   string
+	srvImageDir string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run bootstrap services from an inventory file",
+}
+
+var serveBootCmd = &cobra.Command{
+	Use:   "boot",
+	Short: "Serve iPXE scripts, boot images and cloud-init data to nodes over HTTP",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if srvFile == "" {
+			return errors.New("--file is required")
+		}
+		if srvConfig == "" {
+			return errors.New("--config is required")
+		}
+		conf, err := boot.Load(srvConfig)
+		if err != nil {
+			return err
+		}
+		doc, err := loadInventory(srvFile)
+		if err != nil {
+			return err
+		}
+		if srvImageDir != "" {
+			if fi, err := os.Stat(srvImageDir); err != nil || !fi.IsDir() {
+				return fmt.Errorf("--image-dir %s is not a directory", srvImageDir)
+			}
+		}
+		s, err := boot.NewServer(conf, doc, srvImageDir, os.Stdout)
+		if err != nil {
+			return err
+		}
+		ln, err := net.Listen("tcp", srvListen)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			srv.Shutdown(shutdown) //nolint:errcheck
+		}()
+		fmt.Fprintf(os.Stderr, "Serving boot files for %d node(s) on http://%s/boot.ipxe\n", len(doc.Nodes), ln.Addr())
+		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.AddCommand(serveBootCmd)
+	serveBootCmd.Flags().StringVarP(&srvFile, "file", "f", "", "inventory YAML file (required)")
+	serveBootCmd.Flags().StringVar(&srvConfig, "config", "", "boot parameters YAML, as for export boot (required); without base_url, URLs use the address the node connected to")
+	serveBootCmd.Flags().StringVar(&srvListen, "listen", ":8080", "address to listen on")
+	serveBootCmd.Flags().StringVar(&srvImageDir, "image-dir", "", "directory with kernel, initrd and rootfs images to serve under /boot/")
+}
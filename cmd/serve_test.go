@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeBootFlags(t *testing.T) {
+	dir := configureExport(t)
+	for _, tc := range []struct {
+		file, config, imageDir, want string
+	}{
+		{"", "../examples/boot.yaml", "", "--file is required"},
+		{expFile, "", "", "--config is required"},
+		{expFile, "../examples/boot.yaml", filepath.Join(dir, "missing"), "is not a directory"},
+	} {
+		srvFile, srvConfig, srvImageDir, srvListen = tc.file, tc.config, tc.imageDir, "127.0.0.1:0"
+		if _, err := runCmd(t, serveBootCmd); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected %q, got %v", tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/inventory"
+	"bootstrap/internal/smd"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncFile        string
+	syncSMDURL      string
+	syncApply       bool
+	syncPrune       bool
+	syncPull        bool
+	syncOutput      string
+	syncInsecure    bool
+	syncTimeout     time.Duration
+	syncCredentials bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile an inventory file with a running service",
+}
+
+var syncSMDCmd = &cobra.Command{
+	Use:   "smd",
+	Short: "Diff the inventory against SMD RedfishEndpoints and EthernetInterfaces and apply the changes",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if syncSMDURL == "" {
+			return errors.New("--smd-url is required")
+		}
+		c := smd.NewClient(syncSMDURL, os.Getenv("SMD_TOKEN"), syncInsecure, syncTimeout)
+
+		if syncPull {
+			if syncOutput == "" {
+				return errors.New("--pull requires --output")
+			}
+			if _, err := os.Stat(syncOutput); err == nil {
+				return fmt.Errorf("%s already exists; remove it or pick another --output", syncOutput)
+			}
+			remote, err := c.Get(cmd.Context())
+			if err != nil {
+				return err
+			}
+			doc := smd.ToInventory(remote)
+			out, err := inventory.Marshal(doc)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(syncOutput, out, 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s with %d BMC(s) and %d node(s) from %s\n", syncOutput, len(doc.BMCs), len(doc.Nodes), syncSMDURL)
+			return nil
+		}
+
+		if syncFile == "" {
+			return errors.New("--file is required")
+		}
+		doc, err := loadInventory(syncFile)
+		if err != nil {
+			return err
+		}
+		var creds smd.Credentials
+		if syncCredentials {
+			creds.User, creds.Password = os.Getenv("REDFISH_USER"), os.Getenv("REDFISH_PASSWORD")
+			if creds.User == "" || creds.Password == "" {
+				return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required with --redfish-credentials")
+			}
+		}
+		remote, err := c.Get(cmd.Context())
+		if err != nil {
+			return err
+		}
+		changes := smd.Diff(smd.FromInventory(doc, creds), remote)
+		// SMD may hold objects this inventory never managed, so deletes are opt-in
+		kept := 0
+		if !syncPrune {
+			n := 0
+			for _, ch := range changes {
+				if ch.Action == smd.Delete {
+					kept++
+					continue
+				}
+				changes[n] = ch
+				n++
+			}
+			changes = changes[:n]
+		}
+		printSyncPlan(changes, kept)
+		if !syncApply || len(changes) == 0 {
+			return nil
+		}
+		if err := c.Apply(cmd.Context(), changes); err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d change(s) to %s\n", len(changes), syncSMDURL)
+		return nil
+	},
+}
+
+// printSyncPlan prints changes and the plan summary. kept is the number of objects in SMD
+// but not in the inventory that are left alone without --prune.
+func printSyncPlan(changes []smd.Change, kept int) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tKIND\tID\tFIELDS") //nolint:errcheck
+	counts := map[smd.Action]int{}
+	for _, ch := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ch.Action, ch.Kind, ch.ID, strings.Join(ch.Fields, ",")) //nolint:errcheck
+		counts[ch.Action]++
+	}
+	tw.Flush() //nolint:errcheck
+	fmt.Printf("Plan: %d to create, %d to update, %d to delete\n", counts[smd.Create], counts[smd.Update], counts[smd.Delete])
+	if kept > 0 {
+		fmt.Printf("%d object(s) in SMD but not in the inventory are kept; pass --prune to delete them\n", kept)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncSMDCmd)
+	syncSMDCmd.Flags().StringVarP(&syncFile, "file", "f", "", "inventory YAML file (required unless --pull)")
+	syncSMDCmd.Flags().StringVar(&syncSMDURL, "smd-url", "", "SMD base URL, e.g. https://smd.example:27779 (required; bearer token from SMD_TOKEN)")
+	syncSMDCmd.Flags().BoolVar(&syncApply, "apply", false, "make the create/update/delete calls instead of only printing the plan")
+	syncSMDCmd.Flags().BoolVar(&syncPrune, "prune", false, "also delete RedfishEndpoints and EthernetInterfaces that are in SMD but not in the inventory")
+	syncSMDCmd.Flags().BoolVar(&syncPull, "pull", false, "reverse mode: write a new inventory built from SMD to --output")
+	syncSMDCmd.Flags().StringVarP(&syncOutput, "output", "o", "", "inventory file written by --pull (must not exist)")
+	syncSMDCmd.Flags().BoolVar(&syncInsecure, "insecure", false, "allow insecure TLS to SMD")
+	syncSMDCmd.Flags().DurationVar(&syncTimeout, "timeout", 30*time.Second, "per-request SMD timeout")
+	syncSMDCmd.Flags().BoolVar(&syncCredentials, "redfish-credentials", false, "include REDFISH_USER/REDFISH_PASSWORD in created RedfishEndpoints")
+}
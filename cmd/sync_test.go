@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/internal/inventory"
+)
+
+func TestSyncSMD(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method != http.MethodGet {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			return
+		}
+		switch r.URL.Path {
+		case "/hsm/v2/Inventory/RedfishEndpoints":
+			w.Write([]byte(`{"RedfishEndpoints":[{"ID":"x9000c1s0b0","Type":"NodeBMC","FQDN":"192.168.100.1","IPAddress":"192.168.100.1","MACAddr":"02:23:28:01:30:00","Enabled":true},{"ID":"x9000c2s0b0","FQDN":"192.168.100.2","Enabled":true}]}`)) //nolint:errcheck
+		case "/hsm/v2/State/Components":
+			w.Write([]byte(`{"Components":[{"ID":"x9000c1s0b0n0","Type":"Node","NID":1,"Role":"Compute"}]}`)) //nolint:errcheck
+		case "/hsm/v2/Inventory/ComponentEndpoints":
+			w.Write([]byte(`{"ComponentEndpoints":[]}`)) //nolint:errcheck
+		case "/hsm/v2/Inventory/EthernetInterfaces":
+			w.Write([]byte(`[{"ID":"aabbccddee01","Description":"eth0","MACAddress":"aa:bb:cc:dd:ee:01","IPAddresses":[{"IPAddress":"10.42.0.9","Network":"nmn"}],"ComponentID":"x9000c1s0b0n0"}]`)) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := configureExport(t)
+	syncFile, syncSMDURL, syncApply, syncPrune, syncPull, syncOutput = expFile, server.URL, false, false, false, ""
+	syncInsecure, syncTimeout, syncCredentials = false, 5*time.Second, false
+
+	out, err := runCmd(t, syncSMDCmd)
+	if err != nil {
+		t.Fatalf("plan: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"create  EthernetInterface  022328013000",
+		"update  EthernetInterface  aabbccddee01  IPAddresses",
+		"Plan: 1 to create, 1 to update, 0 to delete",
+		"1 object(s) in SMD but not in the inventory are kept; pass --prune to delete them",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plan missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "x9000c2s0b0") {
+		t.Errorf("plan deletes without --prune:\n%s", out)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("plan made changes: %v", calls)
+	}
+
+	syncApply = true
+	if out, err = runCmd(t, syncSMDCmd); err != nil {
+		t.Fatalf("apply: %v\n%s", err, out)
+	}
+	want := "POST /hsm/v2/Inventory/EthernetInterfaces,PATCH /hsm/v2/Inventory/EthernetInterfaces/aabbccddee01"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("calls = %s, want %s", got, want)
+	}
+
+	calls, syncPrune = nil, true
+	t.Cleanup(func() { syncPrune = false })
+	if out, err = runCmd(t, syncSMDCmd); err != nil {
+		t.Fatalf("apply --prune: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "delete  RedfishEndpoint    x9000c2s0b0") || !strings.Contains(out, "Plan: 1 to create, 1 to update, 1 to delete") {
+		t.Errorf("unexpected --prune plan:\n%s", out)
+	}
+	want = "DELETE /hsm/v2/Inventory/RedfishEndpoints/x9000c2s0b0," + want
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("calls = %s, want %s", got, want)
+	}
+
+	// Reverse mode recovers an inventory
+	syncApply, syncPull, syncOutput = false, true, filepath.Join(dir, "pulled.yaml")
+	if out, err = runCmd(t, syncSMDCmd); err != nil {
+		t.Fatalf("pull: %v\n%s", err, out)
+	}
+	doc, err := inventory.Load(syncOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.BMCs) != 2 || len(doc.Nodes) != 1 || doc.Nodes[0].NID != 1 || doc.Nodes[0].IP != "10.42.0.9" {
+		raw, _ := json.Marshal(doc)
+		t.Fatalf("pulled inventory: %s", raw)
+	}
+	if _, err = runCmd(t, syncSMDCmd); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("pull overwrote %s: %v", syncOutput, err)
+	}
+}
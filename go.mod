@@ -8,7 +8,9 @@ go 1.25
 
 require (
 	github.com/metal-stack/go-ipam v1.14.13
-	github.com/spf13/cobra v1.8.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/spf13/cobra v1.8.1
+	go.etcd.io/bbolt v1.4.3
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -28,7 +30,7 @@ require (
 	github.com/lib/pq v1.10.9 // indirect
 	github.com/montanaflynn/stats v0.7.1 // indirect
 	github.com/redis/go-redis/v9 v9.12.1 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/pflag v1.0.6 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.2 // indirect
 	github.com/xdg-go/stringprep v1.0.4 // indirect
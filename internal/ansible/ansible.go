@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package ansible builds an Ansible inventory from an inventory file: BMCs and nodes with
+// per-host variables, and nodes grouped by cabinet, chassis, role, subrole and group labels.
+package ansible
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"bootstrap/internal/inventory"
+	"bootstrap/internal/xname"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Inventory is an Ansible inventory: every host's variables and the members of every group.
+// BMCs are only in the bmcs group; the cabinet, chassis, role and label groups hold nodes.
+type Inventory struct {
+	HostVars map[string]map[string]any
+	Groups   map[string][]string
+}
+
+var unsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// groupName makes an Ansible-safe group name from a prefix and a value, e.g.
+// ("role", "Compute") -> role_compute, ("label", "gpu-a100") -> label_gpu_a100.
+func groupName(prefix, value string) string {
+	name := unsafe.ReplaceAllString(strings.ToLower(value), "_")
+	if prefix != "" {
+		name = prefix + "_" + name
+	}
+	return name
+}
+
+// Build returns the Ansible inventory for doc. Nodes are named by their hostname, or by
+// xname when they have none; BMCs by xname. Hosts get ansible_host, xname and mac, and
+// nodes also nid, role, subrole and the xname and IP of their BMC where known.
+func Build(doc *inventory.FileFormat) *Inventory {
+	inv := &Inventory{HostVars: map[string]map[string]any{}, Groups: map[string][]string{"bmcs": {}, "nodes": {}}}
+	bmcIP := map[string]string{}
+	for _, b := range doc.BMCs {
+		vars := map[string]any{"xname": b.Xname}
+		setIf(vars, "ansible_host", b.IP)
+		setIf(vars, "mac", b.MAC)
+		inv.HostVars[b.Xname] = vars
+		inv.Groups["bmcs"] = append(inv.Groups["bmcs"], b.Xname)
+		bmcIP[b.Xname] = b.IP
+	}
+	for _, n := range doc.Nodes {
+		name := n.Hostname
+		if name == "" {
+			name = n.Xname
+		}
+		vars := map[string]any{"xname": n.Xname}
+		setIf(vars, "ansible_host", n.IP)
+		setIf(vars, "mac", n.MAC)
+		if n.NID > 0 {
+			vars["nid"] = n.NID
+		}
+		setIf(vars, "role", n.Role)
+		setIf(vars, "subrole", n.SubRole)
+		bmc := n.BMC
+		if bmc == "" {
+			bmc = xname.NodeToBMC(n.Xname)
+		}
+		setIf(vars, "bmc_xname", bmc)
+		setIf(vars, "bmc_ip", bmcIP[bmc])
+		inv.HostVars[name] = vars
+
+		groups := []string{"nodes"}
+		if c := xname.Cabinet(n.Xname); c != "" {
+			groups = append(groups, groupName("cabinet", c))
+		}
+		if c := xname.Chassis(n.Xname); c != "" {
+			groups = append(groups, groupName("chassis", c))
+		}
+		if n.Role != "" {
+			groups = append(groups, groupName("role", n.Role))
+		}
+		if n.SubRole != "" {
+			groups = append(groups, groupName("subrole", n.SubRole))
+		}
+		// Labels are prefixed so that one named like a built-in group (nodes, role_compute)
+		// does not merge into it
+		for _, g := range n.Groups {
+			groups = append(groups, groupName("label", g))
+		}
+		seen := map[string]bool{}
+		for _, g := range groups {
+			if !seen[g] {
+				seen[g] = true
+				inv.Groups[g] = append(inv.Groups[g], name)
+			}
+		}
+	}
+	return inv
+}
+
+func setIf(vars map[string]any, key, value string) {
+	if value != "" {
+		vars[key] = value
+	}
+}
+
+// groupNames returns bmcs and nodes first, then the other groups sorted.
+func (inv *Inventory) groupNames() []string {
+	names := []string{"bmcs", "nodes"}
+	var rest []string
+	for g := range inv.Groups {
+		if g != "bmcs" && g != "nodes" {
+			rest = append(rest, g)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// INI renders the inventory in Ansible INI format. Host variables are written on the host's
+// line in the bmcs or nodes group; the other groups only list host names.
+func (inv *Inventory) INI() []byte {
+	var b strings.Builder
+	for i, g := range inv.groupNames() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", g)
+		for _, h := range inv.Groups[g] {
+			b.WriteString(h)
+			if g == "bmcs" || g == "nodes" {
+				vars := inv.HostVars[h]
+				keys := make([]string, 0, len(vars))
+				for k := range vars {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					v := fmt.Sprint(vars[k])
+					if strings.ContainsAny(v, " \t#;=\"'") {
+						v = fmt.Sprintf("%q", v)
+					}
+					fmt.Fprintf(&b, " %s=%s", k, v)
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// YAML renders the inventory in Ansible YAML format, with every group a child of all.
+func (inv *Inventory) YAML() ([]byte, error) {
+	type group struct {
+		Hosts map[string]map[string]any `yaml:"hosts"`
+	}
+	children := map[string]group{}
+	for g, hosts := range inv.Groups {
+		members := map[string]map[string]any{}
+		for _, h := range hosts {
+			if g == "bmcs" || g == "nodes" {
+				members[h] = inv.HostVars[h]
+			} else {
+				members[h] = nil
+			}
+		}
+		children[g] = group{Hosts: members}
+	}
+	return yaml.Marshal(map[string]any{"all": map[string]any{"children": children}})
+}
+
+// List renders the inventory as dynamic inventory JSON, as returned for --list: every
+// group with its hosts, all with every group as a child, and _meta.hostvars.
+func (inv *Inventory) List() ([]byte, error) {
+	out := map[string]any{
+		"_meta": map[string]any{"hostvars": inv.HostVars},
+		"all":   map[string]any{"children": append([]string{"ungrouped"}, inv.groupNames()...)},
+	}
+	for g, hosts := range inv.Groups {
+		out[g] = map[string]any{"hosts": hosts}
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Host renders the variables of one host as JSON, as returned for --host. Unknown hosts get
+// an empty object.
+func (inv *Inventory) Host(name string) ([]byte, error) {
+	vars := inv.HostVars[name]
+	if vars == nil {
+		vars = map[string]any{}
+	}
+	b, err := json.MarshalIndent(vars, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
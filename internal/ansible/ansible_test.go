@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package ansible
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"bootstrap/internal/inventory"
+
+	"gopkg.in/yaml.v3"
+)
+
+func testDoc() *inventory.FileFormat {
+	return &inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"}},
+		Nodes: []inventory.Entry{
+			{Xname: "x9000c1s0b0n0", MAC: "aa:bb:cc:dd:ee:01", IP: "10.42.0.1", NID: 1, Hostname: "nid000001", Role: "Compute", Groups: []string{"gpu-a100", "nodes", "GPU_A100"}},
+			{Xname: "x9000c3s0b0n0", IP: "10.42.0.2", Role: "Application", SubRole: "UAN", BMC: "x9000c3s0b0"},
+		},
+	}
+}
+
+func TestINI(t *testing.T) {
+	got := string(Build(testDoc()).INI())
+	want := `[bmcs]
+x9000c1s0b0 ansible_host=192.168.100.1 mac=02:23:28:01:30:00 xname=x9000c1s0b0
+
+[nodes]
+nid000001 ansible_host=10.42.0.1 bmc_ip=192.168.100.1 bmc_xname=x9000c1s0b0 mac=aa:bb:cc:dd:ee:01 nid=1 role=Compute xname=x9000c1s0b0n0
+x9000c3s0b0n0 ansible_host=10.42.0.2 bmc_xname=x9000c3s0b0 role=Application subrole=UAN xname=x9000c3s0b0n0
+
+[cabinet_x9000]
+nid000001
+x9000c3s0b0n0
+
+[chassis_x9000c1]
+nid000001
+
+[chassis_x9000c3]
+x9000c3s0b0n0
+
+[label_gpu_a100]
+nid000001
+
+[label_nodes]
+nid000001
+
+[role_application]
+x9000c3s0b0n0
+
+[role_compute]
+nid000001
+
+[subrole_uan]
+x9000c3s0b0n0
+`
+	if got != want {
+		t.Fatalf("INI:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestYAMLAndDynamic(t *testing.T) {
+	inv := Build(testDoc())
+	out, err := inv.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		All struct {
+			Children map[string]struct {
+				Hosts map[string]map[string]any
+			}
+		}
+	}
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.All.Children["nodes"].Hosts["nid000001"]["bmc_ip"] != "192.168.100.1" {
+		t.Errorf("YAML hostvars:\n%s", out)
+	}
+	if _, ok := doc.All.Children["role_compute"].Hosts["nid000001"]; !ok {
+		t.Errorf("YAML groups:\n%s", out)
+	}
+
+	out, err = inv.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list map[string]json.RawMessage
+	if err := json.Unmarshal(out, &list); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(list["chassis_x9000c3"]), `"x9000c3s0b0n0"`) || !strings.Contains(string(list["_meta"]), `"nid": 1`) {
+		t.Errorf("--list:\n%s", out)
+	}
+	if out, _ = inv.Host("nid000001"); !strings.Contains(string(out), `"xname": "x9000c1s0b0n0"`) {
+		t.Errorf("--host:\n%s", out)
+	}
+	if out, _ = inv.Host("unknown"); string(out) != "{}\n" {
+		t.Errorf("--host unknown: %s", out)
+	}
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package baseline defines the declarative firmware baseline file: the firmware version
+// each component should run, the image that provides it, and which hosts it applies to.
+package baseline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Component is the desired firmware for one component type or set of targets.
+type Component struct {
+	Name string `yaml:"name"`
+	// Type is a firmware type preset (cc|nc|bmc|bios); ignored when Targets is set.
+	Type string `yaml:"type,omitempty"`
+	// Targets are FirmwareInventory URIs or glob patterns matched against the members of
+	// each host's FirmwareInventory (see MatchTarget).
+	Targets []string `yaml:"targets,omitempty"`
+	Version string   `yaml:"version"`
+	// ImageURI is the URI the BMC fetches the image from. ImageFile is used instead when
+	// ImageURI is empty and is resolved against an image base URL.
+	ImageURI  string `yaml:"image_uri,omitempty"`
+	ImageFile string `yaml:"image_file,omitempty"`
+	Protocol  string `yaml:"protocol,omitempty"`
+	// Hosts limits the component to hosts whose xname or address matches one of these glob
+	// patterns (e.g. x9000c1*). An empty list matches every host.
+	Hosts []string `yaml:"hosts,omitempty"`
+}
+
+// File is the root of a baseline YAML document.
+type File struct {
+	Components []Component `yaml:"components"`
+}
+
+// Load reads and validates a baseline file.
+func Load(file string) (*File, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc File
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", file, err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("baseline %s: %w", file, err)
+	}
+	return &doc, nil
+}
+
+// Validate checks that every component names its targets, version and image.
+func (f *File) Validate() error {
+	if len(f.Components) == 0 {
+		return errors.New("components[] must not be empty")
+	}
+	var errs []error
+	for i, c := range f.Components {
+		label := c.Name
+		if label == "" {
+			label = fmt.Sprintf("components[%d]", i)
+		}
+		if c.Type == "" && len(c.Targets) == 0 {
+			errs = append(errs, fmt.Errorf("%s: one of type or targets is required", label))
+		}
+		if c.Version == "" {
+			errs = append(errs, fmt.Errorf("%s: version is required", label))
+		}
+		if c.ImageURI == "" && c.ImageFile == "" {
+			errs = append(errs, fmt.Errorf("%s: one of image_uri or image_file is required", label))
+		}
+		for _, t := range c.Targets {
+			switch {
+			case IsTargetPattern(t):
+				if _, err := path.Match(t, ""); err != nil {
+					errs = append(errs, fmt.Errorf("%s: bad target pattern %q: %w", label, t, err))
+				}
+			case !strings.HasPrefix(t, "/redfish/v1/"):
+				errs = append(errs, fmt.Errorf("%s: target %q is neither a FirmwareInventory URI nor a pattern such as */FirmwareInventory/BIOS* (use type for the cc|nc|bmc|bios presets)", label, t))
+			}
+		}
+		for _, p := range c.Hosts {
+			if _, err := path.Match(p, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s: bad host pattern %q: %w", label, p, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Matches reports whether the component applies to a host with the given xname and address.
+func (c Component) Matches(xname, host string) bool {
+	if len(c.Hosts) == 0 {
+		return true
+	}
+	for _, p := range c.Hosts {
+		for _, v := range []string{xname, host} {
+			if v == "" {
+				continue
+			}
+			if ok, _ := path.Match(p, v); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsTargetPattern reports whether a target is a glob pattern rather than a single
+// FirmwareInventory URI.
+func IsTargetPattern(target string) bool {
+	return strings.ContainsAny(target, "*?[")
+}
+
+// MatchTarget reports whether the FirmwareInventory URI uri matches the target pattern.
+// Patterns use path.Match syntax; a pattern that does not start with "/" matches the tail
+// of the URI after any "/", so */FirmwareInventory/BIOS* matches
+// /redfish/v1/UpdateService/FirmwareInventory/BIOS and Node*.BIOS matches .../Node1.BIOS.
+func MatchTarget(pattern, uri string) bool {
+	if ok, _ := path.Match(pattern, uri); ok || strings.HasPrefix(pattern, "/") {
+		return ok
+	}
+	for i := range len(uri) {
+		if uri[i] != '/' {
+			continue
+		}
+		if ok, _ := path.Match(pattern, uri[i+1:]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Image returns the image URI for the component, joining ImageFile to baseURL when no
+// ImageURI is given.
+func (c Component) Image(baseURL string) (string, error) {
+	if c.ImageURI != "" {
+		return c.ImageURI, nil
+	}
+	if baseURL == "" {
+		return "", fmt.Errorf("%s: image_file %s needs an image base URL", c.Name, c.ImageFile)
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(c.ImageFile, "/"), nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeBaseline(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "baseline.yaml")
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoad(t *testing.T) {
+	p := writeBaseline(t, `components:
+  - name: bmc
+    type: bmc
+    version: nc.1.9.8
+    image_uri: http://10.0.0.1/bmc.bin
+  - name: bios
+    targets: [/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS]
+    version: "1.6"
+    image_file: bios-1.6.cap
+    hosts: ["x9000c1*"]
+`)
+	doc, err := Load(p)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(doc.Components) != 2 {
+		t.Fatalf("got %d components, want 2", len(doc.Components))
+	}
+	bios := doc.Components[1]
+	if bios.Version != "1.6" || bios.Hosts[0] != "x9000c1*" {
+		t.Fatalf("unexpected bios component: %+v", bios)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := writeBaseline(t, `components:
+  - name: broken
+    hosts: ["["]
+  - name: bios
+    targets: [BIOS, "Node[0"]
+    version: "1.6"
+    image_file: bios.cap
+`)
+	_, err := Load(p)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"type or targets", "version is required", "image_uri or image_file", "bad host pattern",
+		`target "BIOS" is neither a FirmwareInventory URI nor a pattern`, "bad target pattern"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	c := Component{Hosts: []string{"x9000c1*", "10.1.1.*"}}
+	tests := []struct {
+		xname, host string
+		want        bool
+	}{
+		{"x9000c1s0b0", "192.168.0.1", true},
+		{"x9000c3s0b0", "10.1.1.5", true},
+		{"x9000c3s0b0", "192.168.0.1", false},
+		{"", "10.2.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := c.Matches(tt.xname, tt.host); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.xname, tt.host, got, tt.want)
+		}
+	}
+	if !(Component{}).Matches("x1", "h") {
+		t.Error("component without host patterns should match every host")
+	}
+}
+
+func TestMatchTarget(t *testing.T) {
+	const bios = "/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS"
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"*/FirmwareInventory/Node*.BIOS", true},
+		{"Node?.BIOS", true},
+		{"/redfish/v1/UpdateService/FirmwareInventory/*.BIOS", true},
+		{"/FirmwareInventory/*.BIOS", false},
+		{"*/FirmwareInventory/BMC*", false},
+		{"BIOS*", false},
+	}
+	for _, tt := range tests {
+		if got := MatchTarget(tt.pattern, bios); got != tt.want {
+			t.Errorf("MatchTarget(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	c := Component{Name: "bios", ImageFile: "bios.cap"}
+	if got, _ := c.Image("http://10.0.0.1/images/"); got != "http://10.0.0.1/images/bios.cap" {
+		t.Errorf("Image = %q", got)
+	}
+	if _, err := c.Image(""); err == nil {
+		t.Error("expected error without base URL")
+	}
+	c.ImageURI = "http://other/bios.cap"
+	if got, _ := c.Image(""); got != "http://other/bios.cap" {
+		t.Errorf("Image = %q", got)
+	}
+}
@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package boot renders per-node network boot configuration from an inventory: iPXE scripts,
+// GRUB configs for UEFI HTTP boot and cloud-init NoCloud data, with kernel, initrd and
+// kernel command line chosen by role or group.
+package boot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"bootstrap/internal/inventory"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile holds boot parameters as text/template strings over Data. Empty fields are
+// inherited from the defaults or from earlier matching profiles.
+type Profile struct {
+	// Role, SubRole and Group select the nodes a profile applies to (case-insensitive);
+	// a profile with none of them applies to every node.
+	Role    string `yaml:"role,omitempty"`
+	SubRole string `yaml:"subrole,omitempty"`
+	Group   string `yaml:"group,omitempty"`
+
+	Kernel   string `yaml:"kernel,omitempty"`
+	Initrd   string `yaml:"initrd,omitempty"`
+	Params   string `yaml:"params,omitempty"`
+	UserData string `yaml:"user_data,omitempty"`
+}
+
+// Config is the root of a boot configuration YAML document: default parameters, then
+// profiles applied in order to the nodes they match.
+type Config struct {
+	// BaseURL is where boot files are served from, available to templates as .BaseURL.
+	BaseURL  string `yaml:"base_url,omitempty"`
+	Profile  `yaml:",inline"`
+	Profiles []Profile `yaml:"profiles,omitempty"`
+}
+
+// DefaultUserData is used when no profile sets user_data.
+const DefaultUserData = "#cloud-config\nhostname: {{.Name}}\n"
+
+// Data is what boot templates are executed with: the node entry (.Xname, .NID, .MAC,
+// .Role, ...), .Name (hostname, or xname when empty) and .BaseURL.
+type Data struct {
+	inventory.Entry
+	Name    string
+	BaseURL string
+}
+
+// Params are the rendered boot parameters of one node.
+type Params struct {
+	Kernel   string
+	Initrd   string
+	Cmdline  string
+	UserData string
+	MetaData string
+}
+
+// Load reads and validates a boot configuration file.
+func Load(file string) (*Config, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("parse boot config %s: %w", file, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("boot config %s: %w", file, err)
+	}
+	return &c, nil
+}
+
+// Validate checks that every template parses.
+func (c *Config) Validate() error {
+	var errs []error
+	for i, p := range append([]Profile{c.Profile}, c.Profiles...) {
+		label := "defaults"
+		if i > 0 {
+			label = fmt.Sprintf("profiles[%d]", i-1)
+		}
+		for _, f := range [][2]string{{"kernel", p.Kernel}, {"initrd", p.Initrd}, {"params", p.Params}, {"user_data", p.UserData}} {
+			if _, err := template.New(f[0]).Parse(f[1]); err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", label, f[0], err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p Profile) matches(n inventory.Entry) bool {
+	if p.Role != "" && !strings.EqualFold(p.Role, n.Role) {
+		return false
+	}
+	if p.SubRole != "" && !strings.EqualFold(p.SubRole, n.SubRole) {
+		return false
+	}
+	if p.Group != "" && !slices.ContainsFunc(n.Groups, func(g string) bool { return strings.EqualFold(g, p.Group) }) {
+		return false
+	}
+	return true
+}
+
+// Render returns the boot parameters of node n: the defaults overridden by every matching
+// profile in order, with the templates executed.
+func (c *Config) Render(n inventory.Entry) (Params, error) {
+	eff := c.Profile
+	for _, p := range c.Profiles {
+		if !p.matches(n) {
+			continue
+		}
+		override(&eff.Kernel, p.Kernel)
+		override(&eff.Initrd, p.Initrd)
+		override(&eff.Params, p.Params)
+		override(&eff.UserData, p.UserData)
+	}
+	if eff.UserData == "" {
+		eff.UserData = DefaultUserData
+	}
+	data := Data{Entry: n, Name: n.Hostname, BaseURL: strings.TrimRight(c.BaseURL, "/")}
+	if data.Name == "" {
+		data.Name = n.Xname
+	}
+	var p Params
+	for _, f := range []struct {
+		name string
+		src  string
+		dst  *string
+	}{
+		{"kernel", eff.Kernel, &p.Kernel},
+		{"initrd", eff.Initrd, &p.Initrd},
+		{"params", eff.Params, &p.Cmdline},
+		{"user_data", eff.UserData, &p.UserData},
+	} {
+		tpl, err := template.New(f.name).Parse(f.src)
+		if err != nil {
+			return p, fmt.Errorf("%s: %s: %w", n.Xname, f.name, err)
+		}
+		var b bytes.Buffer
+		if err := tpl.Execute(&b, data); err != nil {
+			return p, fmt.Errorf("%s: %s: %w", n.Xname, f.name, err)
+		}
+		*f.dst = strings.TrimSpace(b.String())
+	}
+	if p.Kernel == "" {
+		return p, fmt.Errorf("%s: no kernel configured", n.Xname)
+	}
+	p.UserData += "\n"
+	p.MetaData = fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", n.Xname, data.Name)
+	return p, nil
+}
+
+func override(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+// MACName returns the MAC in iPXE ${mac:hexhyp} form, e.g. aa-bb-cc-dd-ee-01. It returns
+// an empty string for an invalid MAC.
+func MACName(mac string) string {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return ""
+	}
+	return strings.ReplaceAll(hw.String(), ":", "-")
+}
+
+// IPXE returns the iPXE script that boots node n with p.
+func IPXE(n inventory.Entry, p Params) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#!ipxe\n# %s\n", label(n))
+	ipxeBoot(&b, p)
+	return b.String()
+}
+
+func ipxeBoot(b *strings.Builder, p Params) {
+	args := p.Cmdline
+	if p.Initrd != "" {
+		fmt.Fprintf(b, "initrd --name initrd %s\n", p.Initrd)
+		args = strings.TrimSpace("initrd=initrd " + args)
+	}
+	fmt.Fprintf(b, "kernel %s %s\n", p.Kernel, args)
+	b.WriteString("boot\n")
+}
+
+func label(n inventory.Entry) string {
+	if n.Hostname != "" {
+		return n.Xname + " (" + n.Hostname + ")"
+	}
+	return n.Xname
+}
+
+// Node is a node with its rendered boot parameters.
+type Node struct {
+	Entry  inventory.Entry
+	Params Params
+}
+
+// ChainIPXE is the script that loads the per-MAC script next to it.
+const ChainIPXE = "#!ipxe\nchain --autofree ${mac:hexhyp}.ipxe || goto failed\n:failed\necho No boot script for ${mac}\nshell\n"
+
+// IPXEAll returns one iPXE script that branches on the MAC of the booting interface. MACs
+// are written the way iPXE prints ${mac}, lowercase and colon-separated; nodes whose MAC
+// does not parse get no branch.
+func IPXEAll(nodes []Node) string {
+	var b strings.Builder
+	b.WriteString("#!ipxe\n")
+	for _, n := range nodes {
+		if hw, err := net.ParseMAC(n.Entry.MAC); err == nil {
+			fmt.Fprintf(&b, "iseq ${mac} %s && goto %s ||\n", hw, n.Entry.Xname)
+		}
+	}
+	b.WriteString("echo No boot entry for ${mac}\nshell\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "\n:%s\n# %s\n", n.Entry.Xname, label(n.Entry))
+		ipxeBoot(&b, n.Params)
+	}
+	return b.String()
+}
+
+// GRUBName returns the per-MAC config file name GRUB looks for, e.g.
+// grub.cfg-01-aa-bb-cc-dd-ee-01.
+func GRUBName(mac string) string {
+	return "grub.cfg-01-" + MACName(mac)
+}
+
+// GRUB returns a GRUB config that boots node n with p. http(s) URLs are rewritten to GRUB
+// device syntax, e.g. (http,10.42.0.2:8080)/boot/vmlinuz.
+func GRUB(n inventory.Entry, p Params) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "set default=0\nset timeout=0\n\nmenuentry \"%s\" {\n", label(n))
+	// ; separates commands in GRUB
+	fmt.Fprintf(&b, "    linux %s %s\n", grubPath(p.Kernel), strings.ReplaceAll(p.Cmdline, ";", `\;`))
+	if p.Initrd != "" {
+		fmt.Fprintf(&b, "    initrd %s\n", grubPath(p.Initrd))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func grubPath(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return s
+	}
+	return fmt.Sprintf("(%s,%s)%s", u.Scheme, u.Host, u.EscapedPath())
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package boot
+
+import (
+	"strings"
+	"testing"
+
+	"bootstrap/internal/inventory"
+)
+
+func TestRenderProfiles(t *testing.T) {
+	c, err := Load("../../examples/boot.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compute := inventory.Entry{Xname: "x9000c1s0b0n0", MAC: "AA:BB:CC:DD:EE:01", Hostname: "nid000001", Role: "Compute"}
+	p, err := c.Render(compute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Params{
+		Kernel:   "http://10.42.0.2:8080/boot/vmlinuz",
+		Initrd:   "http://10.42.0.2:8080/boot/initrd.img",
+		Cmdline:  "console=ttyS0,115200 ip=dhcp root=live:http://10.42.0.2:8080/boot/rootfs.squashfs ds=nocloud;s=http://10.42.0.2:8080/cloud-init/x9000c1s0b0n0/",
+		UserData: "#cloud-config\nhostname: nid000001\n",
+		MetaData: "instance-id: x9000c1s0b0n0\nlocal-hostname: nid000001\n",
+	}
+	if p != want {
+		t.Fatalf("compute params:\n%+v\nwant:\n%+v", p, want)
+	}
+
+	// Role and group profiles stack in file order
+	uan := inventory.Entry{Xname: "x9000c3s0b0n0", Role: "application", Groups: []string{"GPU"}}
+	if p, err = c.Render(uan); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(p.Cmdline, "uan-rootfs.squashfs") || p.Initrd != "http://10.42.0.2:8080/boot/initrd-gpu.img" || !strings.Contains(p.UserData, "modprobe") {
+		t.Errorf("uan params: %+v", p)
+	}
+
+	if _, err := (&Config{}).Render(compute); err == nil || !strings.Contains(err.Error(), "no kernel") {
+		t.Errorf("expected a missing kernel error, got %v", err)
+	}
+	if err := (&Config{Profile: Profile{Params: "{{.Nope"}}).Validate(); err == nil {
+		t.Error("expected a template error")
+	}
+}
+
+func TestScripts(t *testing.T) {
+	n := inventory.Entry{Xname: "x9000c1s0b0n0", MAC: "AA:BB:CC:DD:EE:01", Hostname: "nid000001"}
+	p := Params{Kernel: "http://10.42.0.2:8080/boot/vmlinuz", Initrd: "http://10.42.0.2:8080/boot/initrd.img", Cmdline: "ip=dhcp ds=nocloud;s=http://10.42.0.2:8080/ci/"}
+
+	if got, want := IPXE(n, p), "#!ipxe\n# x9000c1s0b0n0 (nid000001)\ninitrd --name initrd http://10.42.0.2:8080/boot/initrd.img\nkernel http://10.42.0.2:8080/boot/vmlinuz initrd=initrd ip=dhcp ds=nocloud;s=http://10.42.0.2:8080/ci/\nboot\n"; got != want {
+		t.Errorf("IPXE:\n%s\nwant:\n%s", got, want)
+	}
+	if MACName(n.MAC) != "aa-bb-cc-dd-ee-01" || GRUBName(n.MAC) != "grub.cfg-01-aa-bb-cc-dd-ee-01" {
+		t.Errorf("names: %s %s", MACName(n.MAC), GRUBName(n.MAC))
+	}
+
+	dashed := inventory.Entry{Xname: "x9000c1s0b0n1", MAC: "AA-BB-CC-DD-EE-02"}
+	all := IPXEAll([]Node{{Entry: n, Params: p}, {Entry: dashed, Params: p}})
+	if !strings.Contains(all, "iseq ${mac} aa:bb:cc:dd:ee:01 && goto x9000c1s0b0n0 ||\n") || !strings.Contains(all, "\n:x9000c1s0b0n0\n") ||
+		!strings.Contains(all, "iseq ${mac} aa:bb:cc:dd:ee:02 && goto x9000c1s0b0n1 ||\n") {
+		t.Errorf("IPXEAll:\n%s", all)
+	}
+
+	grub := GRUB(n, p)
+	for _, want := range []string{
+		"    linux (http,10.42.0.2:8080)/boot/vmlinuz ip=dhcp ds=nocloud\\;s=http://10.42.0.2:8080/ci/\n",
+		"    initrd (http,10.42.0.2:8080)/boot/initrd.img\n",
+	} {
+		if !strings.Contains(grub, want) {
+			t.Errorf("GRUB missing %q:\n%s", want, grub)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package boot
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/inventory"
+)
+
+// Server serves what a node needs to network boot: the chain script at /boot.ipxe, the
+// per-MAC scripts it loads at /<mac>.ipxe, files from ImageDir under /boot/, and NoCloud
+// data under /cloud-init/<xname>/.
+type Server struct {
+	conf     *Config
+	imageDir string
+	log      io.Writer
+
+	byMAC   map[string]inventory.Entry
+	byXname map[string]inventory.Entry
+	byIP    map[string]string
+	mu      sync.Mutex
+}
+
+// NewServer indexes the nodes of doc by every MAC, xname and IP and checks that each one
+// renders with conf. An empty imageDir disables /boot/. Requests are logged to log.
+func NewServer(conf *Config, doc *inventory.FileFormat, imageDir string, log io.Writer) (*Server, error) {
+	s := &Server{
+		conf:     conf,
+		imageDir: imageDir,
+		log:      log,
+		byMAC:    map[string]inventory.Entry{},
+		byXname:  map[string]inventory.Entry{},
+		byIP:     map[string]string{},
+	}
+	for _, n := range doc.Nodes {
+		if _, err := conf.Render(n); err != nil {
+			return nil, err
+		}
+		s.byXname[n.Xname] = n
+		macs, ips := []string{n.MAC}, []string{n.IP}
+		for _, i := range n.Interfaces {
+			macs, ips = append(macs, i.MAC), append(ips, i.IP)
+		}
+		for _, m := range macs {
+			if name := MACName(m); name != "" {
+				s.byMAC[name] = n
+			}
+		}
+		for _, ip := range ips {
+			if ip != "" {
+				s.byIP[ip] = n.Xname
+			}
+		}
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /boot.ipxe", func(w http.ResponseWriter, _ *http.Request) {
+		io.WriteString(w, ChainIPXE) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /{script}", s.serveIPXE)
+	mux.HandleFunc("GET /cloud-init/{xname}/{file}", s.serveCloudInit)
+	if s.imageDir != "" {
+		mux.Handle("GET /boot/", http.StripPrefix("/boot/", http.FileServer(http.Dir(s.imageDir))))
+	}
+	return s.logRequests(mux)
+}
+
+func (s *Server) serveIPXE(w http.ResponseWriter, r *http.Request) {
+	mac, ok := strings.CutSuffix(r.PathValue("script"), ".ipxe")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	n, ok := s.byMAC[MACName(mac)]
+	if !ok {
+		http.Error(w, fmt.Sprintf("no node with MAC %s", mac), http.StatusNotFound)
+		return
+	}
+	setXname(w, n.Xname)
+	p, err := s.render(n, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	io.WriteString(w, IPXE(n, p)) //nolint:errcheck
+}
+
+func (s *Server) serveCloudInit(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.byXname[r.PathValue("xname")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	setXname(w, n.Xname)
+	p, err := s.render(n, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	switch r.PathValue("file") {
+	case "meta-data":
+		io.WriteString(w, p.MetaData) //nolint:errcheck
+	case "user-data":
+		io.WriteString(w, p.UserData) //nolint:errcheck
+	case "vendor-data", "network-config":
+		// cloud-init asks for these too; empty means nothing to add.
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// render renders n with the base URL the node reached the server on when base_url is
+// not configured.
+func (s *Server) render(n inventory.Entry, r *http.Request) (Params, error) {
+	conf := *s.conf
+	if conf.BaseURL == "" {
+		conf.BaseURL = "http://" + r.Host
+	}
+	return conf.Render(n)
+}
+
+// logWriter records the status code and resolved xname of a request.
+type logWriter struct {
+	http.ResponseWriter
+	status int
+	xname  string
+}
+
+func (w *logWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// ReadFrom hands bodies copied with io.Copy, such as files served from ImageDir, to the
+// wrapped writer's ReadFrom so they can still be sent with sendfile.
+func (w *logWriter) ReadFrom(r io.Reader) (int64, error) {
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(r)
+	}
+	return io.Copy(w.ResponseWriter, r)
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *logWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func setXname(w http.ResponseWriter, xname string) {
+	if lw, ok := w.(*logWriter); ok {
+		lw.xname = xname
+	}
+}
+
+// logRequests logs one line per request with the xname of the node that made it: from the
+// MAC or xname in the path, else from the client IP, else "-".
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lw := &logWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(lw, r)
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		xname := lw.xname
+		if xname == "" {
+			xname = s.byIP[client]
+		}
+		if xname == "" {
+			xname = "-"
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		fmt.Fprintf(s.log, "%s %s %s %s %d %s\n", time.Now().Format(time.RFC3339), client, r.Method, r.URL.Path, lw.status, xname)
+	})
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package boot
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bootstrap/internal/inventory"
+)
+
+func TestServer(t *testing.T) {
+	images := t.TempDir()
+	if err := os.WriteFile(filepath.Join(images, "vmlinuz"), []byte("kernel"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc := &inventory.FileFormat{Nodes: []inventory.Entry{{
+		Xname: "x9000c1s0b0n0", MAC: "aa:bb:cc:dd:ee:01", IP: "127.0.0.1", Hostname: "nid000001",
+		Interfaces: []inventory.Interface{{Name: "eth1", MAC: "aa:bb:cc:dd:ee:02"}},
+	}}}
+	conf := &Config{Profile: Profile{Kernel: "{{.BaseURL}}/boot/vmlinuz", Params: "ds=nocloud;s={{.BaseURL}}/cloud-init/{{.Xname}}/"}}
+	var log bytes.Buffer
+	s, err := NewServer(conf, doc, images, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close() //nolint:errcheck
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	for _, tc := range []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/boot.ipxe", 200, "chain --autofree ${mac:hexhyp}.ipxe"},
+		{"/aa-bb-cc-dd-ee-01.ipxe", 200, "kernel " + srv.URL + "/boot/vmlinuz ds=nocloud;s=" + srv.URL + "/cloud-init/x9000c1s0b0n0/\n"},
+		{"/aa-bb-cc-dd-ee-02.ipxe", 200, "# x9000c1s0b0n0"},
+		{"/aa-bb-cc-dd-ee-ff.ipxe", 404, "no node with MAC"},
+		{"/boot/vmlinuz", 200, "kernel"},
+		{"/cloud-init/x9000c1s0b0n0/meta-data", 200, "instance-id: x9000c1s0b0n0\nlocal-hostname: nid000001\n"},
+		{"/cloud-init/x9000c1s0b0n0/user-data", 200, "#cloud-config\nhostname: nid000001\n"},
+		{"/cloud-init/x9000c1s0b0n0/vendor-data", 200, ""},
+		{"/cloud-init/x1000c0s0b0n0/meta-data", 404, ""},
+	} {
+		status, body := get(tc.path)
+		if status != tc.status || !strings.Contains(body, tc.want) {
+			t.Errorf("GET %s: %d\n%s\nwant %d containing %q", tc.path, status, body, tc.status, tc.want)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("expected 9 log lines:\n%s", log.String())
+	}
+	// The kernel download is attributed to the node by its IP
+	for i, want := range map[int]string{
+		1: " GET /aa-bb-cc-dd-ee-01.ipxe 200 x9000c1s0b0n0",
+		3: " GET /aa-bb-cc-dd-ee-ff.ipxe 404 x9000c1s0b0n0",
+		4: " 127.0.0.1 GET /boot/vmlinuz 200 x9000c1s0b0n0",
+	} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("log line %d: %q, want suffix %q", i, lines[i], want)
+		}
+	}
+}
+
+// readFromRecorder is a ResponseWriter that records whether ReadFrom was used.
+type readFromRecorder struct {
+	*httptest.ResponseRecorder
+	readFrom bool
+}
+
+func (r *readFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	r.readFrom = true
+	return io.Copy(r.ResponseRecorder, src)
+}
+
+func TestLogWriterDelegates(t *testing.T) {
+	rec := &readFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	lw := &logWriter{ResponseWriter: rec, status: http.StatusOK}
+	// Hide strings.Reader's WriteTo so that io.Copy goes through ReadFrom, as for a file
+	if n, err := io.Copy(lw, struct{ io.Reader }{strings.NewReader("kernel")}); err != nil || n != 6 {
+		t.Fatalf("io.Copy = %d, %v", n, err)
+	}
+	if !rec.readFrom || rec.Body.String() != "kernel" || lw.status != http.StatusOK {
+		t.Errorf("ReadFrom not delegated: readFrom=%v body=%q status=%d", rec.readFrom, rec.Body.String(), lw.status)
+	}
+	if err := http.NewResponseController(lw).Flush(); err != nil {
+		t.Errorf("Flush through Unwrap: %v", err)
+	}
+	if !rec.Flushed {
+		t.Error("the wrapped writer was not flushed")
+	}
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package catalog describes the firmware images on hand: which component and version each
+// provides and which hardware (Manufacturer/Model) it is built for, so that the right image
+// can be picked for each BMC.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"bootstrap/internal/version"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoCompatible is returned by Select when no image fits the hardware.
+var ErrNoCompatible = errors.New("no compatible image in catalog")
+
+// Image is one firmware image in the catalog.
+type Image struct {
+	Name string `yaml:"name,omitempty"`
+	// Component is the firmware type preset the image updates (cc|nc|bmc|bios).
+	Component string `yaml:"component"`
+	Version   string `yaml:"version"`
+	// Manufacturer and Models are case-insensitive glob patterns (e.g. "HPE", "EX4*")
+	// matched against the Manufacturer and Model of the BMC's Manager and Chassis. An empty
+	// Manufacturer matches any; at least one of them must be given.
+	Manufacturer string   `yaml:"manufacturer,omitempty"`
+	Models       []string `yaml:"models,omitempty"`
+	// URI is the URI the BMC fetches the image from. Path is used instead when URI is empty
+	// and is resolved against an image base URL.
+	URI    string `yaml:"uri,omitempty"`
+	Path   string `yaml:"path,omitempty"`
+	SHA256 string `yaml:"sha256,omitempty"`
+
+	// File is the catalog file the image was read from.
+	File string `yaml:"-"`
+}
+
+// Catalog is the root of a catalog YAML document.
+type Catalog struct {
+	Images []Image `yaml:"images"`
+}
+
+// Product is the Manufacturer and Model reported by one Redfish resource.
+type Product struct {
+	Manufacturer string
+	Model        string
+}
+
+func (p Product) String() string {
+	return strings.TrimSpace(p.Manufacturer + " " + p.Model)
+}
+
+// Load reads a catalog file, or every *.yaml and *.yml file in a directory, and validates it.
+func Load(p string) (*Catalog, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{p}
+	if fi.IsDir() {
+		files = nil
+		for _, glob := range []string{"*.yaml", "*.yml"} {
+			m, err := filepath.Glob(filepath.Join(p, glob))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, m...)
+		}
+		sort.Strings(files)
+		if len(files) == 0 {
+			return nil, fmt.Errorf("catalog directory %s has no *.yaml files", p)
+		}
+	}
+	var cat Catalog
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var doc Catalog
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("parse catalog %s: %w", f, err)
+		}
+		for i := range doc.Images {
+			doc.Images[i].File = f
+		}
+		cat.Images = append(cat.Images, doc.Images...)
+	}
+	if err := cat.Validate(); err != nil {
+		return nil, fmt.Errorf("catalog %s: %w", p, err)
+	}
+	return &cat, nil
+}
+
+// Validate checks that every image names its component, version, hardware and location.
+func (c *Catalog) Validate() error {
+	if len(c.Images) == 0 {
+		return errors.New("images[] must not be empty")
+	}
+	var errs []error
+	for i, img := range c.Images {
+		label := img.Label()
+		if label == "" {
+			label = fmt.Sprintf("images[%d]", i)
+		}
+		if img.File != "" {
+			label = filepath.Base(img.File) + ": " + label
+		}
+		if img.Component == "" {
+			errs = append(errs, fmt.Errorf("%s: component is required", label))
+		}
+		if img.Version == "" {
+			errs = append(errs, fmt.Errorf("%s: version is required", label))
+		}
+		if img.Manufacturer == "" && len(img.Models) == 0 {
+			errs = append(errs, fmt.Errorf("%s: one of manufacturer or models is required", label))
+		}
+		if img.URI == "" && img.Path == "" {
+			errs = append(errs, fmt.Errorf("%s: one of uri or path is required", label))
+		}
+		for _, p := range append([]string{img.Manufacturer}, img.Models...) {
+			if _, err := path.Match(p, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s: bad pattern %q: %w", label, p, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Label names the image in messages: its name, or its URI or path.
+func (i Image) Label() string {
+	switch {
+	case i.Name != "":
+		return i.Name
+	case i.URI != "":
+		return i.URI
+	}
+	return i.Path
+}
+
+// Compatible reports whether the image is built for the given hardware.
+func (i Image) Compatible(p Product) bool {
+	if i.Manufacturer != "" && !match(i.Manufacturer, p.Manufacturer) {
+		return false
+	}
+	if len(i.Models) == 0 {
+		return true
+	}
+	for _, m := range i.Models {
+		if match(m, p.Model) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern, s string) bool {
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(strings.TrimSpace(s)))
+	return ok
+}
+
+// Location returns the image URI, joining Path to baseURL when no URI is given.
+func (i Image) Location(baseURL string) (string, error) {
+	if i.URI != "" {
+		return i.URI, nil
+	}
+	if baseURL == "" {
+		return "", fmt.Errorf("%s: path %s needs an image base URL", i.Label(), i.Path)
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(i.Path, "/"), nil
+}
+
+// Select returns the image for component that is compatible with any of products and
+// accepted by accept (nil accepts all). When several fit, the newest version according to
+// p wins; two different images at that version are ambiguous and an error.
+func (c *Catalog) Select(component string, products []Product, p version.Parser, accept func(Image) bool) (Image, error) {
+	var candidates []Image
+	for _, img := range c.Images {
+		if !strings.EqualFold(img.Component, component) || (accept != nil && !accept(img)) {
+			continue
+		}
+		for _, prod := range products {
+			if img.Compatible(prod) {
+				candidates = append(candidates, img)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		names := make([]string, 0, len(products))
+		for _, prod := range products {
+			if s := prod.String(); s != "" && !slices.Contains(names, s) {
+				names = append(names, s)
+			}
+		}
+		if len(names) == 0 {
+			names = []string{"unknown hardware"}
+		}
+		return Image{}, fmt.Errorf("%w for %s %s", ErrNoCompatible, component, strings.Join(names, ", "))
+	}
+
+	best := candidates[0]
+	var tied []Image
+	for _, img := range candidates[1:] {
+		cmp, ok := version.CompareStrings(p, img.Version, best.Version)
+		if !ok {
+			// Fall back to string order so the choice is stable
+			cmp = strings.Compare(img.Version, best.Version)
+		}
+		switch {
+		case cmp > 0:
+			best, tied = img, nil
+		case cmp == 0 && img.URI+img.Path != best.URI+best.Path:
+			tied = append(tied, img)
+		}
+	}
+	if len(tied) > 0 {
+		labels := []string{best.Label()}
+		for _, img := range tied {
+			labels = append(labels, img.Label())
+		}
+		return Image{}, fmt.Errorf("ambiguous catalog: %s %s is provided by %s", component, best.Version, strings.Join(labels, ", "))
+	}
+	return best, nil
+}
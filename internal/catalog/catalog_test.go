@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package catalog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bootstrap/internal/version"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+const ncImages = `images:
+  - name: nc-ex425-1.9.8
+    component: nc
+    version: nc.1.9.8
+    manufacturer: HPE
+    models: ["EX425*", "EX235*"]
+    uri: http://10.0.0.1/nc-ex425-1.9.8.bin
+  - name: nc-ex425-1.10.0
+    component: nc
+    version: nc.1.10.0
+    manufacturer: hpe
+    models: ["EX425*"]
+    path: nc-ex425-1.10.0.bin
+`
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "nc.yaml", ncImages)
+	writeFile(t, dir, "bios.yml", `images:
+  - component: bios
+    version: "1.6"
+    models: ["EX4*"]
+    uri: http://10.0.0.1/bios-1.6.cap
+`)
+	writeFile(t, dir, "README.txt", "not a catalog")
+	cat, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cat.Images) != 3 {
+		t.Fatalf("got %d images, want 3", len(cat.Images))
+	}
+	if filepath.Base(cat.Images[0].File) != "bios.yml" {
+		t.Errorf("images should be loaded in file name order, got %s first", cat.Images[0].File)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := writeFile(t, t.TempDir(), "catalog.yaml", `images:
+  - name: broken
+    component: nc
+`)
+	_, err := Load(p)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"version is required", "one of manufacturer or models", "one of uri or path"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	cat, err := Load(writeFile(t, t.TempDir(), "catalog.yaml", ncImages))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex425 := []Product{{Manufacturer: "HPE", Model: "Windom NC"}, {Manufacturer: "HPE", Model: "EX425 Compute Blade"}}
+	tests := []struct {
+		name     string
+		products []Product
+		accept   func(Image) bool
+		want     string
+		wantErr  string
+	}{
+		{name: "newest compatible", products: ex425, want: "nc-ex425-1.10.0"},
+		{name: "accept filter", products: ex425, accept: func(i Image) bool { return i.Version == "nc.1.9.8" }, want: "nc-ex425-1.9.8"},
+		{name: "other model", products: []Product{{Manufacturer: "HPE", Model: "EX235a"}}, want: "nc-ex425-1.9.8"},
+		{name: "wrong manufacturer", products: []Product{{Manufacturer: "Gigabyte", Model: "EX425"}}, wantErr: "no compatible image in catalog for nc Gigabyte EX425"},
+		{name: "unknown hardware", products: nil, wantErr: "for nc unknown hardware"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := cat.Select("nc", tt.products, version.Dotted, tt.accept)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrNoCompatible) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select failed: %v", err)
+			}
+			if img.Name != tt.want {
+				t.Errorf("selected %s, want %s", img.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectAmbiguous(t *testing.T) {
+	cat := &Catalog{Images: []Image{
+		{Name: "a", Component: "bios", Version: "1.6", Models: []string{"*"}, URI: "http://h/a.cap"},
+		{Name: "b", Component: "bios", Version: "1.6", Models: []string{"*"}, URI: "http://h/b.cap"},
+	}}
+	if _, err := cat.Select("bios", []Product{{Model: "EX425"}}, version.Dotted, nil); err == nil || !strings.Contains(err.Error(), "ambiguous catalog") {
+		t.Fatalf("expected ambiguity error, got %v", err)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	img := Image{Name: "nc", Path: "/nc.bin"}
+	if _, err := img.Location(""); err == nil {
+		t.Fatal("expected error without a base URL")
+	}
+	if got, _ := img.Location("http://10.0.0.1/images/"); got != "http://10.0.0.1/images/nc.bin" {
+		t.Errorf("Location = %s", got)
+	}
+}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package dhcp generates static DHCP reservations for the BMCs and nodes of an inventory
+// as dnsmasq configuration or Kea DHCPv4 JSON.
+package dhcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"bootstrap/internal/inventory"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Subnet holds the options handed out to hosts in one subnet.
+type Subnet struct {
+	// Name tags the subnet in dnsmasq output; it defaults to subnet<N>.
+	Name   string   `yaml:"name,omitempty"`
+	CIDR   string   `yaml:"cidr"`
+	Router string   `yaml:"router,omitempty"`
+	DNS    []string `yaml:"dns,omitempty"`
+	Domain string   `yaml:"domain,omitempty"`
+	// NextServer and Filename point PXE clients at a TFTP/HTTP server and boot file.
+	NextServer string `yaml:"next_server,omitempty"`
+	Filename   string `yaml:"filename,omitempty"`
+
+	network *net.IPNet
+}
+
+// File is the root of a subnet options YAML document.
+type File struct {
+	Subnets []Subnet `yaml:"subnets"`
+}
+
+// Load reads and validates a subnet options file.
+func Load(file string) (*File, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc File
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse subnets %s: %w", file, err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("subnets %s: %w", file, err)
+	}
+	return &doc, nil
+}
+
+// Validate parses every CIDR and address and fills in default names.
+func (f *File) Validate() error {
+	var errs []error
+	names := map[string]bool{}
+	for i := range f.Subnets {
+		s := &f.Subnets[i]
+		if s.Name == "" {
+			s.Name = fmt.Sprintf("subnet%d", i)
+		}
+		if names[s.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate subnet name", s.Name))
+		}
+		names[s.Name] = true
+		_, n, err := net.ParseCIDR(s.CIDR)
+		if err != nil || n.IP.To4() == nil {
+			errs = append(errs, fmt.Errorf("%s: cidr %q is not an IPv4 CIDR", s.Name, s.CIDR))
+			continue
+		}
+		s.network = n
+		for _, ip := range append([]string{s.Router, s.NextServer}, s.DNS...) {
+			if ip != "" && net.ParseIP(ip) == nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not an IP address", s.Name, ip))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subnetOf returns the first subnet containing ip, or nil.
+func (f *File) subnetOf(ip string) *Subnet {
+	if f == nil {
+		return nil
+	}
+	addr := net.ParseIP(ip)
+	for i := range f.Subnets {
+		if s := &f.Subnets[i]; s.network != nil && s.network.Contains(addr) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Reservation is one static MAC to IP mapping.
+type Reservation struct {
+	Hostname string
+	MAC      string
+	IP       string
+}
+
+// Reservations returns a reservation for every MAC and IPv4 address pair of the BMCs and
+// nodes in doc. The primary interface is named after the xname and every other interface
+// after the xname and interface name, e.g. x9000c1s0b0n0-hsn0. Entries without both a
+// MAC and an IPv4 address (such as node placeholders not yet discovered) are skipped.
+func Reservations(doc *inventory.FileFormat) []Reservation {
+	var out []Reservation
+	for _, e := range append(append([]inventory.Entry{}, doc.BMCs...), doc.Nodes...) {
+		seen := map[string]bool{}
+		add := func(name, mac, ip string) {
+			hw, err := net.ParseMAC(mac)
+			if err != nil || seen[hw.String()] || net.ParseIP(ip).To4() == nil {
+				return
+			}
+			seen[hw.String()] = true
+			out = append(out, Reservation{Hostname: name, MAC: hw.String(), IP: ip})
+		}
+		add(e.Xname, e.MAC, e.IP)
+		for _, iface := range e.Interfaces {
+			add(e.Xname+"-"+iface.Name, iface.MAC, iface.IP)
+		}
+	}
+	return out
+}
+
+// Dnsmasq writes dnsmasq configuration: a static dhcp-range with router, DNS, domain and
+// dhcp-boot options per subnet, then a dhcp-host and host-record line per reservation.
+// subnets may be nil; otherwise it must have been validated (Load does this).
+func Dnsmasq(w io.Writer, res []Reservation, subnets *File) error {
+	var b strings.Builder
+	if subnets != nil {
+		for _, s := range subnets.Subnets {
+			fmt.Fprintf(&b, "# %s %s\n", s.Name, s.CIDR)
+			fmt.Fprintf(&b, "dhcp-range=set:%s,%s,static,%s\n", s.Name, s.network.IP, net.IP(s.network.Mask))
+			if s.Router != "" {
+				fmt.Fprintf(&b, "dhcp-option=tag:%s,option:router,%s\n", s.Name, s.Router)
+			}
+			if len(s.DNS) > 0 {
+				fmt.Fprintf(&b, "dhcp-option=tag:%s,option:dns-server,%s\n", s.Name, strings.Join(s.DNS, ","))
+			}
+			if s.Domain != "" {
+				fmt.Fprintf(&b, "dhcp-option=tag:%s,option:domain-name,%s\n", s.Name, s.Domain)
+			}
+			if s.Filename != "" {
+				fmt.Fprintf(&b, "dhcp-boot=tag:%s,%s,,%s\n", s.Name, s.Filename, s.NextServer)
+			}
+			b.WriteString("\n")
+		}
+	}
+	for _, r := range res {
+		fmt.Fprintf(&b, "dhcp-host=%s,%s,%s\n", r.MAC, r.IP, r.Hostname)
+		fmt.Fprintf(&b, "host-record=%s,%s\n", r.Hostname, r.IP)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type keaOption struct {
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+type keaReservation struct {
+	HWAddress string `json:"hw-address"`
+	IPAddress string `json:"ip-address"`
+	Hostname  string `json:"hostname"`
+}
+
+type keaSubnet struct {
+	ID           int              `json:"id"`
+	Subnet       string           `json:"subnet"`
+	OptionData   []keaOption      `json:"option-data,omitempty"`
+	NextServer   string           `json:"next-server,omitempty"`
+	BootFileName string           `json:"boot-file-name,omitempty"`
+	Reservations []keaReservation `json:"reservations"`
+}
+
+// Kea returns a Kea DHCPv4 configuration with a subnet4 entry per subnet holding its
+// options and the reservations inside it. Reservations outside every subnet become global
+// reservations. subnets may be nil; otherwise it must have been validated.
+func Kea(res []Reservation, subnets *File) ([]byte, error) {
+	var dhcp4 struct {
+		Subnet4      []keaSubnet      `json:"subnet4"`
+		Reservations []keaReservation `json:"reservations,omitempty"`
+	}
+	dhcp4.Subnet4 = []keaSubnet{}
+	index := map[*Subnet]int{}
+	if subnets != nil {
+		for i := range subnets.Subnets {
+			s := &subnets.Subnets[i]
+			ks := keaSubnet{ID: i + 1, Subnet: s.network.String(), NextServer: s.NextServer, BootFileName: s.Filename, Reservations: []keaReservation{}}
+			if s.Router != "" {
+				ks.OptionData = append(ks.OptionData, keaOption{Name: "routers", Data: s.Router})
+			}
+			if len(s.DNS) > 0 {
+				ks.OptionData = append(ks.OptionData, keaOption{Name: "domain-name-servers", Data: strings.Join(s.DNS, ", ")})
+			}
+			if s.Domain != "" {
+				ks.OptionData = append(ks.OptionData, keaOption{Name: "domain-name", Data: s.Domain})
+			}
+			index[s] = len(dhcp4.Subnet4)
+			dhcp4.Subnet4 = append(dhcp4.Subnet4, ks)
+		}
+	}
+	for _, r := range res {
+		kr := keaReservation{HWAddress: r.MAC, IPAddress: r.IP, Hostname: r.Hostname}
+		if s := subnets.subnetOf(r.IP); s != nil {
+			i := index[s]
+			dhcp4.Subnet4[i].Reservations = append(dhcp4.Subnet4[i].Reservations, kr)
+			continue
+		}
+		dhcp4.Reservations = append(dhcp4.Reservations, kr)
+	}
+	out, err := json.MarshalIndent(map[string]any{"Dhcp4": dhcp4}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
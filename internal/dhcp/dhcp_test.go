@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package dhcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"bootstrap/internal/inventory"
+)
+
+func testDoc() *inventory.FileFormat {
+	return &inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"}},
+		Nodes: []inventory.Entry{
+			{Xname: "x9000c1s0b0n0", MAC: "AA:BB:CC:DD:EE:01", IP: "10.42.0.1", Interfaces: []inventory.Interface{
+				{Name: "eth0", MAC: "aa:bb:cc:dd:ee:01", IP: "10.42.0.1"},
+				{Name: "hsn0", MAC: "02:00:00:00:00:01", IP: "10.150.0.1"},
+			}},
+			{Xname: "x9000c1s0b0n1"}, // placeholder, not discovered yet
+		},
+	}
+}
+
+func testSubnets(t *testing.T) *File {
+	t.Helper()
+	f := &File{Subnets: []Subnet{
+		{CIDR: "192.168.100.0/24", Router: "192.168.100.254"},
+		{Name: "nmn", CIDR: "10.42.0.0/24", Router: "10.42.0.254", DNS: []string{"10.42.0.2", "10.42.0.3"}, Domain: "cluster.local", NextServer: "10.42.0.2", Filename: "ipxe.efi"},
+	}}
+	if err := f.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestDnsmasq(t *testing.T) {
+	var b strings.Builder
+	if err := Dnsmasq(&b, Reservations(testDoc()), testSubnets(t)); err != nil {
+		t.Fatal(err)
+	}
+	want := `# subnet0 192.168.100.0/24
+dhcp-range=set:subnet0,192.168.100.0,static,255.255.255.0
+dhcp-option=tag:subnet0,option:router,192.168.100.254
+
+# nmn 10.42.0.0/24
+dhcp-range=set:nmn,10.42.0.0,static,255.255.255.0
+dhcp-option=tag:nmn,option:router,10.42.0.254
+dhcp-option=tag:nmn,option:dns-server,10.42.0.2,10.42.0.3
+dhcp-option=tag:nmn,option:domain-name,cluster.local
+dhcp-boot=tag:nmn,ipxe.efi,,10.42.0.2
+
+dhcp-host=02:23:28:01:30:00,192.168.100.1,x9000c1s0b0
+host-record=x9000c1s0b0,192.168.100.1
+dhcp-host=aa:bb:cc:dd:ee:01,10.42.0.1,x9000c1s0b0n0
+host-record=x9000c1s0b0n0,10.42.0.1
+dhcp-host=02:00:00:00:00:01,10.150.0.1,x9000c1s0b0n0-hsn0
+host-record=x9000c1s0b0n0-hsn0,10.150.0.1
+`
+	if b.String() != want {
+		t.Fatalf("dnsmasq:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestKea(t *testing.T) {
+	out, err := Kea(Reservations(testDoc()), testSubnets(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		Dhcp4 struct {
+			Subnet4 []struct {
+				ID           int         `json:"id"`
+				Subnet       string      `json:"subnet"`
+				NextServer   string      `json:"next-server"`
+				BootFileName string      `json:"boot-file-name"`
+				OptionData   []keaOption `json:"option-data"`
+				Reservations []keaReservation
+			}
+			Reservations []keaReservation
+		}
+	}
+	if err := json.Unmarshal(out, &cfg); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	s := cfg.Dhcp4.Subnet4
+	if len(s) != 2 || s[1].ID != 2 || s[1].Subnet != "10.42.0.0/24" || s[1].NextServer != "10.42.0.2" || s[1].BootFileName != "ipxe.efi" {
+		t.Fatalf("subnets: %s", out)
+	}
+	if len(s[1].OptionData) != 3 || s[1].OptionData[1] != (keaOption{Name: "domain-name-servers", Data: "10.42.0.2, 10.42.0.3"}) {
+		t.Errorf("option-data: %+v", s[1].OptionData)
+	}
+	if len(s[0].Reservations) != 1 || len(s[1].Reservations) != 1 || s[1].Reservations[0].Hostname != "x9000c1s0b0n0" {
+		t.Errorf("subnet reservations: %s", out)
+	}
+	if g := cfg.Dhcp4.Reservations; len(g) != 1 || g[0] != (keaReservation{HWAddress: "02:00:00:00:00:01", IPAddress: "10.150.0.1", Hostname: "x9000c1s0b0n0-hsn0"}) {
+		t.Errorf("global reservations: %+v", g)
+	}
+}
+
+func TestValidateSubnets(t *testing.T) {
+	f := &File{Subnets: []Subnet{{Name: "a", CIDR: "10.0.0.0/33"}, {Name: "a", CIDR: "10.1.0.0/24", DNS: []string{"dns.example"}}}}
+	err := f.Validate()
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{`a: cidr "10.0.0.0/33" is not an IPv4 CIDR`, "a: duplicate subnet name", `a: "dns.example" is not an IP address`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in %v", want, err)
+		}
+	}
+}
@@ -19,7 +19,9 @@ import (
 )
 
 // UpdateNodes reads existing nodes for reservations, discovers bootable NICs per BMC,
-// allocates IPs, and returns the new nodes list.
+// allocates IPs, and returns the new nodes list: the existing nodes in their original order,
+// each replaced by its rediscovered record, followed by newly discovered nodes. Nodes of
+// BMCs that could not be reached, or that no longer report them, are kept as they were.
 // nodeStartIP is an optional IP address to start node allocation from (skips all IPs before it)
 func UpdateNodes(doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP string, user, pass string, insecure bool, timeout time.Duration) ([]inventory.Entry, error) {
 	// Create allocator for node IPs
@@ -85,9 +87,6 @@ func UpdateNodes(doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP s
 				continue
 			}
 
-			// Use only the first bootable MAC for PXE booting
-			mac := sysMacs.MACs[0]
-
 			// Generate node xname with proper node number
 			// For single-system BMCs, use node 0
 			// For multi-system BMCs, use the system index as node number
@@ -106,10 +105,75 @@ func UpdateNodes(doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP s
 					return nil, fmt.Errorf("ip allocate for %s: %w", nodeX, err)
 				}
 			}
-			out = append(out, inventory.Entry{Xname: nodeX, MAC: mac, IP: ipStr})
+			out = append(out, discoveredNode(existing, nodeX, b.Xname, ipStr, sysMacs))
+		}
+	}
+	return mergeNodes(doc.Nodes, out), nil
+}
+
+// mergeNodes returns existing with each node replaced by its entry in discovered, followed
+// by the discovered nodes that were not in existing.
+func mergeNodes(existing, discovered []inventory.Entry) []inventory.Entry {
+	out := make([]inventory.Entry, 0, len(existing)+len(discovered))
+	used := make(map[string]bool, len(discovered))
+	for _, n := range existing {
+		if d := findByXname(discovered, n.Xname); d != nil {
+			n = *d
+			used[n.Xname] = true
+		}
+		out = append(out, n)
+	}
+	for _, d := range discovered {
+		if !used[d.Xname] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// discoveredNode builds the node record for a discovered system. Fields of an existing
+// record that discovery cannot know (NID, role, groups, annotations, ...) are kept; the
+// boot MAC and IP, the parent BMC and the discovered interfaces are filled in, and a
+// hostname is derived from the NID when there is none.
+func discoveredNode(existing *inventory.Entry, nodeX, bmcX, ip string, sys redfish.SystemMACs) inventory.Entry {
+	n := inventory.Entry{Xname: nodeX}
+	if existing != nil {
+		n = *existing
+	}
+	// Use only the first bootable MAC for PXE booting
+	n.MAC, n.IP, n.BMC = sys.MACs[0], ip, bmcX
+	if n.Hostname == "" && n.NID > 0 {
+		n.Hostname = inventory.NIDHostname(n.NID)
+	}
+
+	ifaces := make([]inventory.Interface, 0, len(sys.Interfaces)+len(n.Interfaces))
+	seen := map[string]bool{}
+	for i, nic := range sys.Interfaces {
+		iface := inventory.Interface{Name: nic.ID, MAC: nic.MAC}
+		if iface.Name == "" {
+			iface.Name = fmt.Sprintf("nic%d", i)
+		}
+		// Keep what was recorded for the same interface before
+		for _, old := range n.Interfaces {
+			if old.MAC == nic.MAC || (old.MAC == "" && old.Name == iface.Name) {
+				iface.IP, iface.Network = old.IP, old.Network
+			}
+		}
+		if nic.MAC == n.MAC {
+			iface.IP = ip
+		}
+		ifaces = append(ifaces, iface)
+		seen[nic.MAC] = true
+		seen["name:"+iface.Name] = true
+	}
+	// Interfaces added by hand that discovery does not report (e.g. high-speed network)
+	for _, old := range n.Interfaces {
+		if !seen[old.MAC] && !seen["name:"+old.Name] {
+			ifaces = append(ifaces, old)
 		}
 	}
-	return out, nil
+	n.Interfaces = ifaces
+	return n
 }
 
 func findByXname(list []inventory.Entry, x string) *inventory.Entry {
@@ -5,9 +5,15 @@
 package discover
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"bootstrap/internal/inventory"
+	"bootstrap/internal/redfish"
 )
 
 func TestFindByXname(t *testing.T) {
@@ -60,3 +66,92 @@ func TestFindByXname(t *testing.T) {
 		})
 	}
 }
+
+func TestDiscoveredNodeKeepsExistingFields(t *testing.T) {
+	existing := &inventory.Entry{
+		Xname:       "x1000c0s0b0n0",
+		NID:         7,
+		Role:        "Compute",
+		Groups:      []string{"batch"},
+		Annotations: map[string]string{"rack": "r12"},
+		Interfaces: []inventory.Interface{
+			{Name: "hsn0", MAC: "02:00:00:00:00:99", Network: "hsn"},
+			{Name: "eth0", MAC: "aa:bb:cc:dd:ee:01", Network: "nmn"},
+		},
+	}
+	sys := redfish.SystemMACs{
+		SystemPath: "/redfish/v1/Systems/Node0",
+		MACs:       []string{"aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:02"},
+		Interfaces: []redfish.NIC{{ID: "eth0", MAC: "aa:bb:cc:dd:ee:01"}, {ID: "eth1", MAC: "aa:bb:cc:dd:ee:02"}},
+	}
+	got := discoveredNode(existing, "x1000c0s0b0n0", "x1000c0s0b0", "10.0.0.7", sys)
+	want := inventory.Entry{
+		Xname:       "x1000c0s0b0n0",
+		MAC:         "aa:bb:cc:dd:ee:01",
+		IP:          "10.0.0.7",
+		NID:         7,
+		Hostname:    "nid000007",
+		Role:        "Compute",
+		Groups:      []string{"batch"},
+		BMC:         "x1000c0s0b0",
+		Annotations: map[string]string{"rack": "r12"},
+		Interfaces: []inventory.Interface{
+			{Name: "eth0", MAC: "aa:bb:cc:dd:ee:01", IP: "10.0.0.7", Network: "nmn"},
+			{Name: "eth1", MAC: "aa:bb:cc:dd:ee:02"},
+			{Name: "hsn0", MAC: "02:00:00:00:00:99", Network: "hsn"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("discoveredNode mismatch:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestUpdateNodesKeepsUndiscoveredNodes(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/redfish/v1/Systems":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"}]}`))
+		case "/redfish/v1/Systems/Node0/EthernetInterfaces":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0/EthernetInterfaces/eth0"}]}`))
+		case "/redfish/v1/Systems/Node0/EthernetInterfaces/eth0":
+			_, _ = w.Write([]byte(`{"Id":"eth0","MACAddress":"AA:BB:CC:DD:EE:01"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	unreachable := inventory.Entry{
+		Xname:      "x1000c0s1b0n0",
+		MAC:        "aa:bb:cc:dd:ee:02",
+		IP:         "10.42.0.2",
+		BMC:        "x1000c0s1b0",
+		NID:        2,
+		Role:       "Compute",
+		Groups:     []string{"batch"},
+		Interfaces: []inventory.Interface{{Name: "hsn0", MAC: "02:00:00:00:00:02", Network: "hsn"}},
+	}
+	manual := inventory.Entry{Xname: "x1000c0s9b0n0", IP: "10.42.0.9", Role: "Service"}
+	doc := &inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x1000c0s1b0", IP: "127.0.0.1:1"},
+			{Xname: "x1000c0s0b0", IP: strings.TrimPrefix(ts.URL, "https://")},
+		},
+		Nodes: []inventory.Entry{unreachable, manual},
+	}
+
+	nodes, err := UpdateNodes(doc, "10.42.0.0/24", "10.42.0.0/24", "", "u", "p", true, 2*time.Second)
+	if err != nil {
+		t.Fatalf("UpdateNodes failed: %v", err)
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("got %d nodes, want 3: %+v", len(nodes), nodes)
+	}
+	if !reflect.DeepEqual(nodes[0], unreachable) || !reflect.DeepEqual(nodes[1], manual) {
+		t.Errorf("existing nodes changed:\n%+v\n%+v", nodes[0], nodes[1])
+	}
+	if n := nodes[2]; n.Xname != "x1000c0s0b0n0" || n.MAC != "aa:bb:cc:dd:ee:01" || n.BMC != "x1000c0s0b0" || n.IP == "" {
+		t.Errorf("unexpected discovered node: %+v", n)
+	}
+}
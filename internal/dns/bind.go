@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ZoneOptions are the SOA and NS settings of generated BIND zones.
+type ZoneOptions struct {
+	Domain string
+	// Nameserver is the primary name server, relative to Domain unless it ends in a dot.
+	Nameserver string
+	// NameserverIP adds an address record for Nameserver when it is inside Domain. It is
+	// required then, unless Nameserver is one of the host names.
+	NameserverIP string
+	// Admin is the responsible mailbox in SOA form, e.g. hostmaster (relative to Domain).
+	Admin string
+	TTL   time.Duration
+}
+
+// ErrNameserverAddress is returned by Zones when the name server is inside the domain but
+// the zone would have no address record for it, which BIND refuses to load.
+var ErrNameserverAddress = errors.New("name server inside the domain has no address record")
+
+// Zone is one generated BIND zone: the forward zone for the domain or an in-addr.arpa /
+// ip6.arpa reverse zone.
+type Zone struct {
+	Name    string
+	records []string
+}
+
+// Zones returns the forward zone with A/AAAA records for every name of every host (CNAMEs
+// are not used, so every alias resolves directly) and one reverse zone per IPv4 /24 and
+// IPv6 /64 with a PTR record for the canonical name.
+func Zones(hosts []Host, opts ZoneOptions) ([]Zone, error) {
+	domain := strings.Trim(opts.Domain, ".")
+	if domain == "" {
+		return nil, fmt.Errorf("a domain is required for zone files")
+	}
+	forward := Zone{Name: domain}
+	ip := net.ParseIP(opts.NameserverIP)
+	if opts.NameserverIP != "" && ip == nil {
+		return nil, fmt.Errorf("nameserver IP %q is not an IP address", opts.NameserverIP)
+	}
+	if ns, ok := nameInZone(opts.Nameserver, domain); ok {
+		switch {
+		case ip != nil:
+			forward.records = append(forward.records, record(ns, addressType(ip), ip.String()))
+		case !hasName(hosts, ns):
+			return nil, fmt.Errorf("%s.%s: %w", ns, domain, ErrNameserverAddress)
+		}
+	}
+	reverse := map[string]*Zone{}
+	for _, h := range hosts {
+		for _, n := range h.Names {
+			forward.records = append(forward.records, record(n, addressType(h.IP), h.IP.String()))
+		}
+		zone, name := reverseName(h.IP)
+		if reverse[zone] == nil {
+			reverse[zone] = &Zone{Name: zone}
+		}
+		reverse[zone].records = append(reverse[zone].records, record(name, "PTR", fqdn(h.Names[0], domain)+"."))
+	}
+	zones := []Zone{forward}
+	names := make([]string, 0, len(reverse))
+	for z := range reverse {
+		names = append(names, z)
+	}
+	sort.Strings(names)
+	for _, z := range names {
+		zones = append(zones, *reverse[z])
+	}
+	return zones, nil
+}
+
+// nameInZone returns name relative to domain and whether it is inside domain. Names without
+// a trailing dot are relative to domain already.
+func nameInZone(name, domain string) (string, bool) {
+	if !strings.HasSuffix(name, ".") {
+		return name, name != ""
+	}
+	rel, ok := strings.CutSuffix(strings.ToLower(name), "."+strings.ToLower(domain)+".")
+	return rel, ok && rel != ""
+}
+
+func hasName(hosts []Host, name string) bool {
+	for _, h := range hosts {
+		for _, n := range h.Names {
+			if strings.EqualFold(n, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func addressType(ip net.IP) string {
+	if ip.To4() != nil {
+		return "A"
+	}
+	return "AAAA"
+}
+
+func record(name, typ, data string) string {
+	return fmt.Sprintf("%-24s IN %-4s %s", name, typ, data)
+}
+
+// reverseName returns the reverse zone of ip (its /24 or /64) and the record name of ip
+// within it.
+func reverseName(ip net.IP) (zone, name string) {
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.in-addr.arpa", v4[2], v4[1], v4[0]), strconv.Itoa(int(v4[3]))
+	}
+	nibbles := make([]string, 0, 32)
+	for i := len(ip) - 1; i >= 0; i-- {
+		nibbles = append(nibbles, fmt.Sprintf("%x", ip[i]&0xf), fmt.Sprintf("%x", ip[i]>>4))
+	}
+	return strings.Join(nibbles[16:], ".") + ".ip6.arpa", strings.Join(nibbles[:16], ".")
+}
+
+// Render returns the zone file with the given SOA serial.
+func (z Zone) Render(opts ZoneOptions, serial uint32) string {
+	domain := strings.Trim(opts.Domain, ".")
+	abs := func(name string) string {
+		if strings.HasSuffix(name, ".") {
+			return name
+		}
+		return name + "." + domain + "."
+	}
+	ttl := int(opts.TTL / time.Second)
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n$TTL %d\n", z.Name, ttl)
+	fmt.Fprintf(&b, "@ IN SOA %s %s (\n", abs(opts.Nameserver), abs(opts.Admin))
+	fmt.Fprintf(&b, "    %d ; serial\n    3600 ; refresh\n    900 ; retry\n    1209600 ; expire\n    %d ; minimum\n)\n", serial, ttl)
+	fmt.Fprintf(&b, "@ IN NS %s\n\n", abs(opts.Nameserver))
+	for _, r := range z.records {
+		b.WriteString(r + "\n")
+	}
+	return b.String()
+}
+
+var serialLine = regexp.MustCompile(`(?m)^\s*(\d+) ; serial$`)
+
+// Serial returns the SOA serial for z given the zone file written last time (nil when there
+// is none): the previous serial when the zone is unchanged, otherwise the next date-based
+// serial (YYYYMMDDnn) that is greater than the previous one.
+func (z Zone) Serial(opts ZoneOptions, previous []byte, now time.Time) uint32 {
+	base := uint32(now.Year()*1000000 + int(now.Month())*10000 + now.Day()*100)
+	m := serialLine.FindSubmatch(previous)
+	if m == nil {
+		return base
+	}
+	old, err := strconv.ParseUint(string(m[1]), 10, 32)
+	if err != nil {
+		return base
+	}
+	if z.Render(opts, uint32(old)) == string(previous) {
+		return uint32(old)
+	}
+	if uint32(old) >= base {
+		return uint32(old) + 1
+	}
+	return base
+}
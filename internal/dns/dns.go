@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package dns generates forward and reverse name data for the BMCs and nodes of an
+// inventory: /etc/hosts, CoreDNS hosts plugin files and BIND zone files.
+package dns
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"bootstrap/internal/inventory"
+)
+
+// NameData is what name templates are executed with: the inventory entry (so .Xname,
+// .NID, .Hostname, .Role, ... work) and, for extra interfaces, the interface name and
+// network.
+type NameData struct {
+	inventory.Entry
+	Interface string
+	Network   string
+}
+
+// Templates are text/template name patterns, e.g. {{.Xname}}, nid{{printf "%06d" .NID}} or
+// {{.Xname}}-bmc. The first name of BMC and Node that is not empty is the canonical
+// name of the primary address; the others become aliases. Interface names extra
+// interfaces that have an IP.
+type Templates struct {
+	BMC       []string
+	Node      []string
+	Interface string
+}
+
+// DefaultTemplates name BMCs and nodes by xname, add the node hostname as an alias, and
+// name extra interfaces <xname>-<interface>.
+var DefaultTemplates = Templates{
+	BMC:       []string{"{{.Xname}}"},
+	Node:      []string{"{{.Xname}}", "{{.Hostname}}"},
+	Interface: "{{.Xname}}-{{.Interface}}",
+}
+
+// Host is one address and its names; Names[0] is the canonical name.
+type Host struct {
+	IP    net.IP
+	Names []string
+}
+
+var label = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// Hosts returns a Host for the primary address of every BMC and node and for every extra
+// interface with an IP, in inventory order. Entries without a valid IP, such as node
+// placeholders or host:port endpoints, are skipped.
+func Hosts(doc *inventory.FileFormat, t Templates) ([]Host, error) {
+	parse := func(list []string) ([]*template.Template, error) {
+		var out []*template.Template
+		for _, s := range list {
+			tpl, err := template.New(s).Parse(s)
+			if err != nil {
+				return nil, fmt.Errorf("name template %q: %w", s, err)
+			}
+			out = append(out, tpl)
+		}
+		return out, nil
+	}
+	bmc, err := parse(t.BMC)
+	if err != nil {
+		return nil, err
+	}
+	node, err := parse(t.Node)
+	if err != nil {
+		return nil, err
+	}
+	iface, err := parse([]string{t.Interface})
+	if err != nil {
+		return nil, err
+	}
+	if len(bmc) == 0 || len(node) == 0 {
+		return nil, fmt.Errorf("at least one BMC and one node name template is required")
+	}
+
+	var hosts []Host
+	// owner maps each name given so far (DNS names are case-insensitive) to the host it
+	// names, so that a template giving the same name to two hosts is caught
+	owner := map[string]string{}
+	names := func(tpls []*template.Template, data NameData) ([]string, error) {
+		var out []string
+		seen := map[string]bool{}
+		who := data.Xname
+		if data.Interface != "" {
+			who += " " + data.Interface
+		}
+		for _, tpl := range tpls {
+			var b bytes.Buffer
+			if err := tpl.Execute(&b, data); err != nil {
+				return nil, fmt.Errorf("%s: %w", data.Xname, err)
+			}
+			name := strings.TrimSpace(b.String())
+			if name == "" || seen[name] {
+				continue
+			}
+			if !label.MatchString(name) {
+				return nil, fmt.Errorf("%s: template %q gives invalid name %q", data.Xname, tpl.Name(), name)
+			}
+			key := strings.ToLower(name)
+			if prev, ok := owner[key]; ok {
+				if prev == who {
+					continue
+				}
+				return nil, fmt.Errorf("template %q gives %s the name %q of %s", tpl.Name(), who, name, prev)
+			}
+			owner[key] = who
+			seen[name] = true
+			out = append(out, name)
+		}
+		return out, nil
+	}
+	add := func(tpls []*template.Template, entries []inventory.Entry) error {
+		for _, e := range entries {
+			if ip := net.ParseIP(e.IP); ip != nil {
+				n, err := names(tpls, NameData{Entry: e})
+				if err != nil {
+					return err
+				}
+				if len(n) > 0 {
+					hosts = append(hosts, Host{IP: ip, Names: n})
+				}
+			}
+			for _, in := range e.Interfaces {
+				ip := net.ParseIP(in.IP)
+				if ip == nil || in.IP == e.IP || t.Interface == "" {
+					continue
+				}
+				n, err := names(iface, NameData{Entry: e, Interface: in.Name, Network: in.Network})
+				if err != nil {
+					return err
+				}
+				if len(n) > 0 {
+					hosts = append(hosts, Host{IP: ip, Names: n})
+				}
+			}
+		}
+		return nil
+	}
+	if err := add(bmc, doc.BMCs); err != nil {
+		return nil, err
+	}
+	if err := add(node, doc.Nodes); err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// fqdn appends domain to name; it returns name unchanged when domain is empty.
+func fqdn(name, domain string) string {
+	if domain == "" {
+		return name
+	}
+	return name + "." + strings.Trim(domain, ".")
+}
+
+// HostsFile writes /etc/hosts lines: the address, the FQDN when domain is set, then the
+// short names.
+func HostsFile(w io.Writer, hosts []Host, domain string) error {
+	var b strings.Builder
+	for _, h := range hosts {
+		fields := []string{h.IP.String()}
+		if domain != "" {
+			fields = append(fields, fqdn(h.Names[0], domain))
+		}
+		fields = append(fields, h.Names...)
+		b.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CoreDNS writes a file for the CoreDNS hosts plugin: every name qualified with domain. The
+// plugin answers PTR queries for the first name of each line.
+func CoreDNS(w io.Writer, hosts []Host, domain string) error {
+	var b strings.Builder
+	zone := strings.Trim(domain, ".")
+	if zone == "" {
+		zone = "."
+	}
+	fmt.Fprintf(&b, "# CoreDNS hosts plugin file, e.g. in the Corefile:\n#   %s {\n#       hosts /etc/coredns/cluster.hosts\n#   }\n", zone)
+	for _, h := range hosts {
+		fields := []string{h.IP.String()}
+		for _, n := range h.Names {
+			fields = append(fields, fqdn(n, domain))
+		}
+		b.WriteString(strings.Join(fields, " ") + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package dns
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/internal/inventory"
+)
+
+func testDoc() *inventory.FileFormat {
+	return &inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x9000c1s0b0", IP: "192.168.100.1"},
+			{Xname: "x9000c1s1b0", IP: "127.0.0.1:8443"}, // endpoint, skipped
+		},
+		Nodes: []inventory.Entry{
+			{Xname: "x9000c1s0b0n0", IP: "10.42.0.1", NID: 1, Interfaces: []inventory.Interface{
+				{Name: "eth0", IP: "10.42.0.1"},
+				{Name: "hsn0", IP: "fd00::1:5", Network: "hsn"},
+			}},
+			{Xname: "x9000c1s0b0n1", NID: 2}, // placeholder, skipped
+		},
+	}
+}
+
+func TestHostsTemplates(t *testing.T) {
+	hosts, err := Hosts(testDoc(), Templates{
+		BMC:       []string{"{{.Xname}}-bmc"},
+		Node:      []string{`nid{{printf "%06d" .NID}}`, "{{.Xname}}"},
+		Interface: "{{.Xname}}-{{.Network}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := HostsFile(&b, hosts, "cluster.local"); err != nil {
+		t.Fatal(err)
+	}
+	want := "192.168.100.1\tx9000c1s0b0-bmc.cluster.local\tx9000c1s0b0-bmc\n" +
+		"10.42.0.1\tnid000001.cluster.local\tnid000001\tx9000c1s0b0n0\n" +
+		"fd00::1:5\tx9000c1s0b0n0-hsn.cluster.local\tx9000c1s0b0n0-hsn\n"
+	if b.String() != want {
+		t.Fatalf("hosts:\n%q\nwant:\n%q", b.String(), want)
+	}
+
+	b.Reset()
+	if err := CoreDNS(&b, hosts, "cluster.local"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "\n10.42.0.1 nid000001.cluster.local x9000c1s0b0n0.cluster.local\n") {
+		t.Errorf("coredns:\n%s", b.String())
+	}
+
+	if _, err := Hosts(testDoc(), Templates{BMC: []string{"{{.Xname}} bmc"}, Node: []string{"{{.Xname}}"}}); err == nil || !strings.Contains(err.Error(), `invalid name "x9000c1s0b0 bmc"`) {
+		t.Errorf("expected an invalid name error, got %v", err)
+	}
+	if _, err := Hosts(testDoc(), Templates{BMC: []string{"{{.Nope}}"}, Node: []string{"{{.Xname}}"}}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+
+	// Nodes without a NID all get nid000000
+	doc := testDoc()
+	doc.Nodes = append(doc.Nodes, inventory.Entry{Xname: "x9000c1s1b0n0", IP: "10.42.0.2"}, inventory.Entry{Xname: "x9000c1s1b0n1", IP: "10.42.0.3"})
+	doc.Nodes[0].NID = 0
+	_, err = Hosts(doc, Templates{BMC: []string{"{{.Xname}}"}, Node: []string{`nid{{printf "%06d" .NID}}`}})
+	if err == nil || !strings.Contains(err.Error(), `gives x9000c1s1b0n0 the name "nid000000" of x9000c1s0b0n0`) {
+		t.Errorf("expected a duplicate name error, got %v", err)
+	}
+}
+
+func TestZones(t *testing.T) {
+	hosts, err := Hosts(testDoc(), DefaultTemplates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := ZoneOptions{Domain: "cluster.local", Nameserver: "ns1", NameserverIP: "10.42.0.2", Admin: "hostmaster", TTL: time.Hour}
+	zones, err := Zones(hosts, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, z := range zones {
+		names = append(names, z.Name)
+	}
+	if got := strings.Join(names, " "); got != "cluster.local 0.0.0.0.0.0.0.0.0.0.0.0.0.0.d.f.ip6.arpa 0.42.10.in-addr.arpa 100.168.192.in-addr.arpa" {
+		t.Fatalf("zones = %s", got)
+	}
+	now := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+	fwd := zones[0].Render(opts, zones[0].Serial(opts, nil, now))
+	for _, want := range []string{
+		"$ORIGIN cluster.local.\n$TTL 3600\n@ IN SOA ns1.cluster.local. hostmaster.cluster.local. (\n    2025101900 ; serial\n",
+		"@ IN NS ns1.cluster.local.\n",
+		"ns1                      IN A    10.42.0.2\n",
+		"x9000c1s0b0n0            IN A    10.42.0.1\n",
+		"x9000c1s0b0n0-hsn0       IN AAAA fd00::1:5\n",
+	} {
+		if !strings.Contains(fwd, want) {
+			t.Errorf("forward zone missing %q:\n%s", want, fwd)
+		}
+	}
+	if rev := zones[1].Render(opts, 1); !strings.Contains(rev, "5.0.0.0.1.0.0.0.0.0.0.0.0.0.0.0 IN PTR  x9000c1s0b0n0-hsn0.cluster.local.\n") {
+		t.Errorf("ip6 reverse zone:\n%s", rev)
+	}
+	if rev := zones[2].Render(opts, 1); !strings.Contains(rev, "1                        IN PTR  x9000c1s0b0n0.cluster.local.\n") {
+		t.Errorf("reverse zone:\n%s", rev)
+	}
+
+	checkNSAddresses(t, fwd)
+
+	// Serial: kept when unchanged, bumped past the previous one otherwise
+	z := zones[0]
+	if s := z.Serial(opts, []byte(z.Render(opts, 2025101903)), now); s != 2025101903 {
+		t.Errorf("unchanged zone serial = %d", s)
+	}
+	changed := strings.Replace(z.Render(opts, 2025101903), "10.42.0.1", "10.42.0.9", 1)
+	if s := z.Serial(opts, []byte(changed), now); s != 2025101904 {
+		t.Errorf("changed zone serial = %d", s)
+	}
+	old := strings.Replace(changed, "2025101903", "2024010105", 1)
+	if s := z.Serial(opts, []byte(old), now); s != 2025101900 {
+		t.Errorf("serial from an older day = %d", s)
+	}
+}
+
+// checkNSAddresses fails the test when an NS record of the zone file names a server inside
+// the zone that has no A/AAAA record in it, like named-checkzone does.
+func checkNSAddresses(t *testing.T, zone string) {
+	t.Helper()
+	var origin string
+	var servers []string
+	addrs := map[string]bool{}
+	for _, line := range strings.Split(zone, "\n") {
+		f := strings.Fields(line)
+		switch {
+		case len(f) == 2 && f[0] == "$ORIGIN":
+			origin = f[1]
+		case len(f) == 4 && f[0] == "@" && f[2] == "NS":
+			servers = append(servers, f[3])
+		case len(f) == 4 && f[1] == "IN" && (f[2] == "A" || f[2] == "AAAA"):
+			addrs[f[0]+"."+origin] = true
+		}
+	}
+	for _, ns := range servers {
+		if strings.HasSuffix(ns, "."+origin) && !addrs[ns] {
+			t.Errorf("NS %s is inside %s but has no A/AAAA record:\n%s", ns, origin, zone)
+		}
+	}
+}
+
+func TestZonesNameserverAddress(t *testing.T) {
+	hosts, err := Hosts(testDoc(), DefaultTemplates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, nameserver, ip string
+		wantErr              bool
+	}{
+		{"relative with IP", "ns1", "10.42.0.2", false},
+		{"absolute with IP", "ns1.cluster.local.", "10.42.0.2", false},
+		{"inventory host", "x9000c1s0b0n0", "", false},
+		{"outside the domain", "ns1.example.org.", "", false},
+		{"relative without IP", "ns1", "", true},
+		{"absolute without IP", "ns1.Cluster.Local.", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ZoneOptions{Domain: "cluster.local", Nameserver: tt.nameserver, NameserverIP: tt.ip, Admin: "hostmaster", TTL: time.Hour}
+			zones, err := Zones(hosts, opts)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNameserverAddress) {
+					t.Fatalf("expected ErrNameserverAddress, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkNSAddresses(t, zones[0].Render(opts, 1))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package fanout runs per-BMC work concurrently under global, per-cabinet and per-chassis
+// limits derived from xnames. Work is handed out round-robin across chassis so that one
+// chassis with many BMCs does not hold up the others.
+package fanout
+
+import (
+	"strconv"
+	"sync"
+
+	"bootstrap/internal/xname"
+)
+
+// Limits bounds how many items run at once. Zero or a negative value means no limit at
+// that level. Items without a parsable xname are only subject to the global limit.
+type Limits struct {
+	Global     int
+	PerCabinet int
+	PerChassis int
+}
+
+type group struct {
+	cabinet string
+	chassis string
+	queue   []int
+}
+
+// Run calls fn(i) for every index of xnames, concurrently within limits, and returns when
+// all calls have returned. Within a chassis items start in their original order.
+func Run(xnames []string, limits Limits, fn func(i int)) {
+	var groups []*group
+	byKey := map[string]*group{}
+	for i, x := range xnames {
+		chassis := xname.Chassis(x)
+		key := chassis
+		if key == "" {
+			// No chassis to share: the item is a group of its own
+			key = "#" + strconv.Itoa(i)
+		}
+		g := byKey[key]
+		if g == nil {
+			g = &group{cabinet: xname.Cabinet(x), chassis: chassis}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.queue = append(g.queue, i)
+	}
+
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	running := 0
+	perCabinet := map[string]int{}
+	perChassis := map[string]int{}
+	allowed := func(g *group) bool {
+		return (limits.Global <= 0 || running < limits.Global) &&
+			(limits.PerCabinet <= 0 || g.cabinet == "" || perCabinet[g.cabinet] < limits.PerCabinet) &&
+			(limits.PerChassis <= 0 || g.chassis == "" || perChassis[g.chassis] < limits.PerChassis)
+	}
+
+	var wg sync.WaitGroup
+	next := 0 // round-robin position in groups
+	mu.Lock()
+	for remaining := len(xnames); remaining > 0; {
+		picked := -1
+		for k := range groups {
+			gi := (next + k) % len(groups)
+			if len(groups[gi].queue) > 0 && allowed(groups[gi]) {
+				picked = gi
+				break
+			}
+		}
+		if picked < 0 {
+			// Everything left is blocked by a limit; wait for a running item to finish
+			cond.Wait()
+			continue
+		}
+		g := groups[picked]
+		i := g.queue[0]
+		g.queue = g.queue[1:]
+		next = picked + 1
+		remaining--
+		running++
+		perCabinet[g.cabinet]++
+		perChassis[g.chassis]++
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(i)
+			mu.Lock()
+			running--
+			perCabinet[g.cabinet]--
+			perChassis[g.chassis]--
+			mu.Unlock()
+			cond.Signal()
+		}()
+	}
+	mu.Unlock()
+	wg.Wait()
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fanout
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/internal/xname"
+)
+
+// trackRun runs Run with a short sleeping fn and returns the peak concurrency per key
+// function along with the start order.
+func trackRun(t *testing.T, xnames []string, limits Limits, key func(string) string) (map[string]int, []int) {
+	t.Helper()
+	var mu sync.Mutex
+	current, peak := map[string]int{}, map[string]int{}
+	var order []int
+	Run(xnames, limits, func(i int) {
+		k := key(xnames[i])
+		mu.Lock()
+		order = append(order, i)
+		current[k]++
+		current["*"]++
+		peak[k] = max(peak[k], current[k])
+		peak["*"] = max(peak["*"], current["*"])
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		current[k]--
+		current["*"]--
+		mu.Unlock()
+	})
+	if len(order) != len(xnames) {
+		t.Fatalf("ran %d of %d items", len(order), len(xnames))
+	}
+	return peak, order
+}
+
+var hosts = []string{
+	"x1000c0s0b0", "x1000c0s1b0", "x1000c0s2b0",
+	"x1000c1s0b0", "x1000c1s1b0",
+	"x2000c0s0b0", "x2000c0s1b0",
+	"",
+}
+
+func TestRunPerChassisLimit(t *testing.T) {
+	peak, _ := trackRun(t, hosts, Limits{PerChassis: 1}, xname.Chassis)
+	for k, n := range peak {
+		if k != "*" && k != "" && n > 1 {
+			t.Errorf("chassis %s ran %d at once, limit 1", k, n)
+		}
+	}
+	if peak["*"] < 3 {
+		t.Errorf("expected chassis to run in parallel, peak %d", peak["*"])
+	}
+}
+
+func TestRunPerCabinetAndGlobalLimit(t *testing.T) {
+	peak, _ := trackRun(t, hosts, Limits{Global: 3, PerCabinet: 2}, xname.Cabinet)
+	if peak["*"] > 3 {
+		t.Errorf("global peak %d exceeds 3", peak["*"])
+	}
+	for _, cab := range []string{"x1000", "x2000"} {
+		if peak[cab] > 2 {
+			t.Errorf("cabinet %s ran %d at once, limit 2", cab, peak[cab])
+		}
+	}
+}
+
+func TestRunRoundRobinAcrossChassis(t *testing.T) {
+	_, order := trackRun(t, hosts, Limits{Global: 1}, xname.Chassis)
+	// One from each chassis (and the xname-less host) before any chassis gets a second turn
+	want := []int{0, 3, 5, 7, 1, 4, 6, 2}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("start order = %v, want %v", order, want)
+		}
+	}
+}
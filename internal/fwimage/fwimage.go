@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package fwimage checks a firmware image URI before it is handed to BMCs: that it is
+// reachable and non-empty, that its content matches an expected digest, and that its
+// filename does not name a different firmware type.
+package fwimage
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options describes what to check.
+type Options struct {
+	URI      string
+	SHA256   string // expected hex digest; empty skips the check
+	SHA512   string // expected hex digest; empty skips the check
+	Type     string // firmware type preset (cc, nc, bmc, bios); empty skips the filename check
+	Insecure bool
+	Timeout  time.Duration
+}
+
+// Result is what the preflight found out about the image.
+type Result struct {
+	Size     int64 // -1 when the server did not report it
+	Filename string
+	Digests  map[string]string // algorithm -> verified hex digest
+}
+
+// typeTokens lists, per firmware type, filename tokens that identify it and tokens that
+// identify a different type.
+var typeTokens = map[string]struct{ own, conflict []string }{
+	"cc":   {own: []string{"cc"}, conflict: []string{"nc", "bios", "uefi"}},
+	"nc":   {own: []string{"nc"}, conflict: []string{"cc", "bios", "uefi"}},
+	"bmc":  {own: []string{"bmc", "cc", "nc"}, conflict: []string{"bios", "uefi"}},
+	"bios": {own: []string{"bios", "uefi"}, conflict: []string{"bmc", "cc", "nc"}},
+}
+
+// Check runs the preflight. Only http and https URIs can be fetched; other schemes fail
+// when a digest is requested and are otherwise only checked by filename.
+func Check(ctx context.Context, opts Options) (Result, error) {
+	res := Result{Size: -1, Digests: map[string]string{}}
+	u, err := url.Parse(opts.URI)
+	if err != nil {
+		return res, fmt.Errorf("image URI: %w", err)
+	}
+	res.Filename = path.Base(u.Path)
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		if opts.SHA256 != "" || opts.SHA512 != "" {
+			return res, fmt.Errorf("cannot verify the digest of a %s image URI", u.Scheme)
+		}
+		return res, checkFilename(res.Filename, opts.Type)
+	}
+
+	hc := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: opts.Insecure}}, //nolint:gosec
+	}
+	if err := probe(ctx, hc, opts.URI, &res); err != nil {
+		return res, err
+	}
+	if res.Size == 0 {
+		return res, errors.New("image is empty")
+	}
+	if err := checkFilename(res.Filename, opts.Type); err != nil {
+		return res, err
+	}
+	if opts.SHA256 != "" || opts.SHA512 != "" {
+		if err := verifyDigests(ctx, hc, opts, &res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// probe learns the size and filename with a HEAD request, falling back to a one-byte
+// ranged GET for servers that do not support HEAD.
+func probe(ctx context.Context, hc *http.Client, uri string, res *Result) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := hc.Do(req)
+	if err == nil {
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode < 300 {
+			res.Size = resp.ContentLength
+			setFilename(resp, res)
+			return nil
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("image not found: HEAD %s: %s", uri, resp.Status)
+		}
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err = hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("image not reachable: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// Content-Range: bytes 0-0/12345
+		if i := strings.LastIndex(resp.Header.Get("Content-Range"), "/"); i >= 0 {
+			if n, err := strconv.ParseInt(resp.Header.Get("Content-Range")[i+1:], 10, 64); err == nil {
+				res.Size = n
+			}
+		}
+	case http.StatusOK:
+		res.Size = resp.ContentLength
+	default:
+		return fmt.Errorf("image not reachable: GET %s: %s", uri, resp.Status)
+	}
+	setFilename(resp, res)
+	return nil
+}
+
+// setFilename prefers a Content-Disposition filename over the URL path.
+func setFilename(resp *http.Response, res *Result) {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		res.Filename = params["filename"]
+	}
+}
+
+// verifyDigests downloads the image once and compares it to the expected digests.
+func verifyDigests(ctx context.Context, hc *http.Client, opts Options, res *Result) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, opts.URI, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("image download: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("image download: GET %s: %s", opts.URI, resp.Status)
+	}
+
+	hashes := map[string]hash.Hash{}
+	var writers []io.Writer
+	if opts.SHA256 != "" {
+		hashes["sha256"] = sha256.New()
+		writers = append(writers, hashes["sha256"])
+	}
+	if opts.SHA512 != "" {
+		hashes["sha512"] = sha512.New()
+		writers = append(writers, hashes["sha512"])
+	}
+	n, err := io.Copy(io.MultiWriter(writers...), resp.Body)
+	if err != nil {
+		return fmt.Errorf("image download: %w", err)
+	}
+	if res.Size >= 0 && n != res.Size {
+		return fmt.Errorf("image size mismatch: server reported %d bytes, downloaded %d", res.Size, n)
+	}
+	res.Size = n
+
+	for alg, want := range map[string]string{"sha256": opts.SHA256, "sha512": opts.SHA512} {
+		if want == "" {
+			continue
+		}
+		got := hex.EncodeToString(hashes[alg].Sum(nil))
+		if !strings.EqualFold(got, strings.TrimSpace(want)) {
+			return fmt.Errorf("image %s mismatch: expected %s, got %s", alg, want, got)
+		}
+		res.Digests[alg] = got
+	}
+	return nil
+}
+
+// checkFilename rejects an image whose filename names a different firmware type than
+// typ and not typ itself. Filenames without any type token pass.
+func checkFilename(filename, typ string) error {
+	tt, ok := typeTokens[strings.ToLower(typ)]
+	if !ok || filename == "" {
+		return nil
+	}
+	tokens := map[string]bool{}
+	for _, t := range strings.FieldsFunc(strings.ToLower(filename), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}) {
+		tokens[t] = true
+	}
+	for _, t := range tt.own {
+		if tokens[t] {
+			return nil
+		}
+	}
+	for _, t := range tt.conflict {
+		if tokens[t] {
+			return fmt.Errorf("image filename %q looks like %s firmware, not %s", filename, t, typ)
+		}
+	}
+	return nil
+}
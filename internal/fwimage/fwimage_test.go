@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fwimage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var image = []byte("firmware image payload")
+
+// imageServer serves image at every path; HEAD is rejected when noHead is set.
+func imageServer(t *testing.T, noHead bool) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && noHead {
+			http.Error(w, "no HEAD", http.StatusMethodNotAllowed)
+			return
+		}
+		http.ServeContent(w, r, "image.bin", time.Time{}, strings.NewReader(string(image)))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestCheck(t *testing.T) {
+	sum := sha256.Sum256(image)
+	good := hex.EncodeToString(sum[:])
+	tests := []struct {
+		name    string
+		noHead  bool
+		path    string
+		opts    Options
+		wantErr string
+	}{
+		{name: "head", path: "/nc-1.9.8.bin"},
+		{name: "ranged get fallback", noHead: true, path: "/nc-1.9.8.bin"},
+		{name: "sha256 ok", path: "/nc-1.9.8.bin", opts: Options{SHA256: strings.ToUpper(good)}},
+		{name: "sha256 mismatch", path: "/nc-1.9.8.bin", opts: Options{SHA256: strings.Repeat("0", 64)}, wantErr: "sha256 mismatch"},
+		{name: "type matches", path: "/nc-1.9.8.bin", opts: Options{Type: "nc"}},
+		{name: "type mismatch", path: "/node-bios-2.1.bin", opts: Options{Type: "bmc"}, wantErr: "looks like bios firmware"},
+		{name: "no type token", path: "/image.bin", opts: Options{Type: "bios"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := imageServer(t, tt.noHead)
+			opts := tt.opts
+			opts.URI = ts.URL + tt.path
+			opts.Insecure = true
+			opts.Timeout = 5 * time.Second
+			res, err := Check(context.Background(), opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+			if res.Size != int64(len(image)) {
+				t.Errorf("Size = %d, want %d", res.Size, len(image))
+			}
+		})
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	_, err := Check(context.Background(), Options{URI: ts.URL + "/missing.bin", Insecure: true, Timeout: 5 * time.Second})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestCheckNonHTTPScheme(t *testing.T) {
+	if _, err := Check(context.Background(), Options{URI: "tftp://10.0.0.1/nc.bin", SHA256: "00"}); err == nil {
+		t.Fatal("expected digest error for tftp URI")
+	}
+	if _, err := Check(context.Background(), Options{URI: "tftp://10.0.0.1/nc.bin", Type: "nc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
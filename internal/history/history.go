@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package history keeps firmware status samples in a local bbolt file so that compliance
+// can be reported over time. Samples are keyed by timestamp, host and target; all samples
+// written by one status run share the run's timestamp.
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var samplesBucket = []byte("samples")
+
+// Sample is the status of one firmware target on one host at one point in time.
+type Sample struct {
+	Time      time.Time `json:"time"`
+	Host      string    `json:"host"`
+	Xname     string    `json:"xname,omitempty"`
+	Target    string    `json:"target"`
+	Version   string    `json:"version"`
+	Requested string    `json:"requested,omitempty"` // --expected-version of the run
+	Status    string    `json:"status"`
+	Policy    string    `json:"policy,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Label names the sample's host, preferring the xname.
+func (s Sample) Label() string {
+	if s.Xname == "" {
+		return s.Host
+	}
+	return s.Xname
+}
+
+// Store is an open history file.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the history file at p. It waits briefly for another process
+// holding the file to release it.
+func Open(p string) (*Store, error) {
+	db, err := bolt.Open(p, 0o644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open history %s: %w", p, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(samplesBucket)
+		return err
+	}); err != nil {
+		db.Close() //nolint:errcheck
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the history file.
+func (s *Store) Close() error { return s.db.Close() }
+
+func key(t time.Time, host, target string) []byte {
+	k := make([]byte, 8, 8+len(host)+1+len(target))
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	k = append(k, host...)
+	k = append(k, 0)
+	return append(k, target...)
+}
+
+// Append stores samples in one transaction. Samples with a zero Time get the current time.
+func (s *Store) Append(samples []Sample) error {
+	now := time.Now().UTC()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(samplesBucket)
+		for _, smp := range samples {
+			if smp.Time.IsZero() {
+				smp.Time = now
+			}
+			v, err := json.Marshal(smp)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(key(smp.Time, smp.Host, smp.Target), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query selects samples. Zero fields do not filter.
+type Query struct {
+	// Host is a glob pattern matched against the host address or xname (e.g. x9000c3*).
+	Host  string
+	Since time.Time
+	Until time.Time
+}
+
+func (q Query) matches(s Sample) bool {
+	if q.Host == "" {
+		return true
+	}
+	for _, v := range []string{s.Host, s.Xname} {
+		if ok, _ := path.Match(q.Host, v); ok && v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Samples returns the samples matching q in time order.
+func (s *Store) Samples(q Query) ([]Sample, error) {
+	if _, err := path.Match(q.Host, ""); err != nil {
+		return nil, fmt.Errorf("bad host pattern %q: %w", q.Host, err)
+	}
+	var out []Sample
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(samplesBucket).Cursor()
+		var k, v []byte
+		if q.Since.IsZero() {
+			k, v = c.First()
+		} else {
+			k, v = c.Seek(key(q.Since, "", "")[:8])
+		}
+		var until []byte
+		if !q.Until.IsZero() {
+			until = key(q.Until.Add(1), "", "")[:8]
+		}
+		for ; k != nil; k, v = c.Next() {
+			if until != nil && bytes.Compare(k[:8], until) >= 0 {
+				break
+			}
+			var smp Sample
+			if err := json.Unmarshal(v, &smp); err != nil {
+				return fmt.Errorf("history entry %x: %w", k, err)
+			}
+			if q.matches(smp) {
+				out = append(out, smp)
+			}
+		}
+		return nil
+	})
+	return out, err
+}
+
+// Point is the compliance of one status run.
+type Point struct {
+	Time      time.Time
+	Total     int
+	Compliant int
+}
+
+// Percent returns the share of compliant samples, 0 to 100.
+func (p Point) Percent() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return 100 * float64(p.Compliant) / float64(p.Total)
+}
+
+// Compliance groups samples by run and counts those for which compliant reports true.
+func Compliance(samples []Sample, compliant func(Sample) bool) []Point {
+	byTime := map[int64]*Point{}
+	for _, s := range samples {
+		p := byTime[s.Time.UnixNano()]
+		if p == nil {
+			p = &Point{Time: s.Time}
+			byTime[s.Time.UnixNano()] = p
+		}
+		p.Total++
+		if compliant(s) {
+			p.Compliant++
+		}
+	}
+	out := make([]Point, 0, len(byTime))
+	for _, p := range byTime {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out
+}
+
+// Change is a status change of one host target between consecutive samples.
+type Change struct {
+	Time     time.Time
+	Label    string
+	Target   string
+	From, To string
+}
+
+// StatusChanges returns, in time order, every change of status between consecutive samples
+// of the same host target.
+func StatusChanges(samples []Sample) []Change {
+	last := map[string]Sample{}
+	var out []Change
+	for _, s := range samples {
+		k := s.Host + "\x00" + s.Target
+		if prev, ok := last[k]; ok && prev.Status != s.Status {
+			out = append(out, Change{Time: s.Time, Label: s.Label(), Target: s.Target, From: prev.Status, To: s.Status})
+		}
+		last[k] = s
+	}
+	return out
+}
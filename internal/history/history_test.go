@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendQueryCompliance(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "history.db")
+	s, err := Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(at time.Time, c3Version, c3Status string) []Sample {
+		return []Sample{
+			{Time: at, Host: "10.0.0.1", Xname: "x9000c1s0b0", Target: "BMC", Version: "1.6", Status: "idle"},
+			{Time: at, Host: "10.0.0.3", Xname: "x9000c3s0b0", Target: "BMC", Version: c3Version, Status: c3Status},
+		}
+	}
+	for i, r := range [][]Sample{
+		run(t0, "1.5", "idle"),
+		run(t0.Add(time.Hour), "1.5", "error"),
+		run(t0.Add(2*time.Hour), "1.6", "idle"),
+	} {
+		if err := s.Append(r); err != nil {
+			t.Fatalf("append run %d: %v", i, err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen to make sure samples were persisted
+	s, err = Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close() //nolint:errcheck
+
+	all, err := s.Samples(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 6 {
+		t.Fatalf("got %d samples, want 6", len(all))
+	}
+	points := Compliance(all, func(s Sample) bool { return s.Version == "1.6" && s.Status != "error" })
+	want := []float64{50, 50, 100}
+	if len(points) != len(want) {
+		t.Fatalf("got %d points, want %d", len(points), len(want))
+	}
+	for i, p := range points {
+		if p.Percent() != want[i] || p.Total != 2 {
+			t.Errorf("point %d = %+v (%.0f%%), want %.0f%%", i, p, p.Percent(), want[i])
+		}
+	}
+
+	c3, err := s.Samples(Query{Host: "x9000c3*", Since: t0.Add(time.Hour), Until: t0.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c3) != 1 || c3[0].Status != "error" {
+		t.Fatalf("time-range query returned %+v", c3)
+	}
+
+	c3, _ = s.Samples(Query{Host: "x9000c3*"})
+	changes := StatusChanges(c3)
+	if len(changes) != 2 || changes[0].From != "idle" || changes[0].To != "error" || changes[1].To != "idle" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+}
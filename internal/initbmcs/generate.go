@@ -7,10 +7,12 @@ package initbmcs
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"bootstrap/internal/inventory"
 	"bootstrap/internal/netalloc"
+	"bootstrap/internal/xname"
 )
 
 func getBmcID(n int) int { return (n + 1) / 2 } //nolint:unused
@@ -51,31 +53,59 @@ func ParseChassisSpec(spec string) map[string]string {
 // bmcSubnet should be in CIDR notation, e.g. "192.168.100.0/24"
 // startIP is an optional IP address to start allocation from (skips all IPs before it)
 func Generate(chassis map[string]string, nodesPerChassis, nodesPerBMC, startNID int, bmcSubnet, startIP string) ([]inventory.Entry, error) {
+	doc, err := GenerateInventory(chassis, nodesPerChassis, nodesPerBMC, startNID, bmcSubnet, startIP)
+	if err != nil {
+		return nil, err
+	}
+	return doc.BMCs, nil
+}
+
+// GenerateInventory creates the BMC entries like Generate, plus a placeholder node entry
+// for each node behind each BMC carrying what is known before discovery: the node xname,
+// its NID and nid-based hostname, the Compute role and the parent BMC. Discovery fills in
+// the MAC and IP later. Chassis are numbered in xname order.
+func GenerateInventory(chassis map[string]string, nodesPerChassis, nodesPerBMC, startNID int, bmcSubnet, startIP string) (inventory.FileFormat, error) {
+	var doc inventory.FileFormat
 	alloc, err := netalloc.NewAllocator(bmcSubnet)
 	if err != nil {
-		return nil, fmt.Errorf("bmc subnet init: %w", err)
+		return doc, fmt.Errorf("bmc subnet init: %w", err)
 	}
 
 	// Reserve all IPs before the start IP if specified
 	if startIP != "" {
 		if err := alloc.ReserveUpTo(startIP); err != nil {
-			return nil, fmt.Errorf("reserve up to start IP: %w", err)
+			return doc, fmt.Errorf("reserve up to start IP: %w", err)
 		}
 	}
 
-	var bmcs []inventory.Entry
+	names := make([]string, 0, len(chassis))
+	for c := range chassis {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+
 	nid := startNID
-	for c, macPref := range chassis {
+	for _, c := range names {
+		macPref := chassis[c]
 		for i := nid; i < nid+nodesPerChassis; i += nodesPerBMC {
 			x := getNCXname(c, i)
 			ip, err := alloc.Next()
 			if err != nil {
-				return nil, fmt.Errorf("allocate IP for %s: %w", x, err)
+				return doc, fmt.Errorf("allocate IP for %s: %w", x, err)
 			}
 			mac := strings.ToLower(getNCMAC(macPref, i))
-			bmcs = append(bmcs, inventory.Entry{Xname: x, MAC: mac, IP: ip})
+			doc.BMCs = append(doc.BMCs, inventory.Entry{Xname: x, MAC: mac, IP: ip})
+			for n := 0; n < nodesPerBMC && i+n < nid+nodesPerChassis; n++ {
+				doc.Nodes = append(doc.Nodes, inventory.Entry{
+					Xname:    xname.BMCXnameToNodeN(x, n),
+					NID:      i + n,
+					Hostname: inventory.NIDHostname(i + n),
+					Role:     "Compute",
+					BMC:      x,
+				})
+			}
 		}
 		nid = nid + nodesPerChassis
 	}
-	return bmcs, nil
+	return doc, nil
 }
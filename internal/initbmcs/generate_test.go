@@ -53,3 +53,28 @@ func TestGenerateWithStartIP(t *testing.T) {
 		t.Fatalf("Generate result mismatch:\n got: %#v\nwant: %#v", bmcs, want)
 	}
 }
+
+func TestGenerateInventoryNodes(t *testing.T) {
+	// NIDs continue across chassis, which are taken in xname order
+	chassis := map[string]string{"x9000c3": "02:23:28:03", "x9000c1": "02:23:28:01"}
+	doc, err := GenerateInventory(chassis, 2, 2, 1, "192.168.100.0/24", "")
+	if err != nil {
+		t.Fatalf("GenerateInventory failed: %v", err)
+	}
+	wantBMCs := []inventory.Entry{
+		{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"},
+		{Xname: "x9000c3s0b1", MAC: "02:23:28:03:30:10", IP: "192.168.100.2"},
+	}
+	if !reflect.DeepEqual(doc.BMCs, wantBMCs) {
+		t.Fatalf("BMCs mismatch:\n got: %#v\nwant: %#v", doc.BMCs, wantBMCs)
+	}
+	wantNodes := []inventory.Entry{
+		{Xname: "x9000c1s0b0n0", NID: 1, Hostname: "nid000001", Role: "Compute", BMC: "x9000c1s0b0"},
+		{Xname: "x9000c1s0b0n1", NID: 2, Hostname: "nid000002", Role: "Compute", BMC: "x9000c1s0b0"},
+		{Xname: "x9000c3s0b1n0", NID: 3, Hostname: "nid000003", Role: "Compute", BMC: "x9000c3s0b1"},
+		{Xname: "x9000c3s0b1n1", NID: 4, Hostname: "nid000004", Role: "Compute", BMC: "x9000c3s0b1"},
+	}
+	if !reflect.DeepEqual(doc.Nodes, wantNodes) {
+		t.Fatalf("Nodes mismatch:\n got: %#v\nwant: %#v", doc.Nodes, wantNodes)
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"fmt"
+	"os"
+
+	"bootstrap/internal/xname"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// APIVersion is the latest inventory schema version, written by Marshal.
+	APIVersion = "bootstrap.openchami.io/v1"
+	// Kind identifies an inventory document.
+	Kind = "Inventory"
+	// LegacyVersion names documents without an apiVersion header.
+	LegacyVersion = "v0"
+)
+
+// migrations upgrade a document one schema version at a time, in order. The last entry
+// upgrades to APIVersion.
+var migrations = []struct {
+	from    string
+	upgrade func(*FileFormat)
+}{
+	{LegacyVersion, upgradeV0},
+}
+
+// upgradeV0 records the parent BMC of nodes, which v0 files only implied through the
+// node xname.
+func upgradeV0(doc *FileFormat) {
+	for i := range doc.Nodes {
+		if doc.Nodes[i].BMC == "" {
+			doc.Nodes[i].BMC = xname.NodeToBMC(doc.Nodes[i].Xname)
+		}
+	}
+}
+
+// Parse decodes an inventory document of any known schema version and upgrades it in
+// memory to APIVersion. It returns the version the document was written in.
+func Parse(raw []byte) (*FileFormat, string, error) {
+	var doc FileFormat
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, "", err
+	}
+	if doc.Kind != "" && doc.Kind != Kind {
+		return nil, "", fmt.Errorf("kind is %q, want %s", doc.Kind, Kind)
+	}
+	from := doc.APIVersion
+	if from == "" {
+		from = LegacyVersion
+	}
+	if from != APIVersion {
+		start := -1
+		for i, m := range migrations {
+			if m.from == from {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return nil, "", fmt.Errorf("unsupported apiVersion %q (latest known is %s)", from, APIVersion)
+		}
+		for _, m := range migrations[start:] {
+			m.upgrade(&doc)
+		}
+	}
+	doc.APIVersion, doc.Kind = APIVersion, Kind
+	return &doc, from, nil
+}
+
+// Load reads an inventory file and upgrades it in memory to the latest schema.
+func Load(file string) (*FileFormat, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, _, err := Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("inventory %s: %w", file, err)
+	}
+	return doc, nil
+}
+
+// Marshal encodes doc in the latest schema, setting the apiVersion/kind header.
+func Marshal(doc *FileFormat) ([]byte, error) {
+	doc.APIVersion, doc.Kind = APIVersion, Kind
+	return yaml.Marshal(doc)
+}
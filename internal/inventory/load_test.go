@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseUpgradesLegacy(t *testing.T) {
+	legacy := `bmcs:
+    - xname: x9000c1s0b0
+      ip: 192.168.100.1
+nodes:
+    - xname: x9000c1s0b0n1
+      mac: aa:bb:cc:dd:ee:01
+      ip: 10.42.0.1
+    - xname: x9000c1s0b0n0
+      bmc: x9000c1s0b1
+`
+	doc, from, err := Parse([]byte(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != LegacyVersion || doc.APIVersion != APIVersion || doc.Kind != Kind {
+		t.Fatalf("from=%s header=%s/%s", from, doc.APIVersion, doc.Kind)
+	}
+	if doc.Nodes[0].BMC != "x9000c1s0b0" {
+		t.Errorf("parent BMC not derived: %+v", doc.Nodes[0])
+	}
+	if doc.Nodes[1].BMC != "x9000c1s0b1" {
+		t.Errorf("explicit parent BMC overwritten: %+v", doc.Nodes[1])
+	}
+
+	out, err := Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "apiVersion: "+APIVersion+"\nkind: Inventory\n") {
+		t.Fatalf("header missing:\n%s", out)
+	}
+	again, from, err := Parse(out)
+	if err != nil || from != APIVersion || len(again.Nodes) != 2 {
+		t.Fatalf("reparse: from=%s err=%v", from, err)
+	}
+}
+
+func TestParseRejectsUnknown(t *testing.T) {
+	for src, want := range map[string]string{
+		"apiVersion: bootstrap.openchami.io/v9\nkind: Inventory\n": "unsupported apiVersion",
+		"apiVersion: bootstrap.openchami.io/v1\nkind: Baseline\n":  "kind is",
+	} {
+		if _, _, err := Parse([]byte(src)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) err=%v, want %q", src, err, want)
+		}
+	}
+}
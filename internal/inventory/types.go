@@ -5,15 +5,52 @@
 // Package inventory defines types for inventory YAML files.
 package inventory
 
-// Entry represents a BMC or Node record in the YAML file.
+import "fmt"
+
+// Entry represents a BMC or Node record in the YAML file. Only Xname is required: node
+// placeholders written by init-bmcs have no MAC or IP until discovery fills them in. The
+// other fields are optional and omitted from the file when empty, so minimal inventories
+// load and round-trip unchanged.
 type Entry struct {
 	Xname string `yaml:"xname"`
-	MAC   string `yaml:"mac"`
-	IP    string `yaml:"ip"`
+	// MAC and IP are those of the primary interface: the management port of a BMC, or the
+	// boot NIC of a node.
+	MAC string `yaml:"mac,omitempty"`
+	IP  string `yaml:"ip,omitempty"`
+
+	NID      int    `yaml:"nid,omitempty"`
+	Hostname string `yaml:"hostname,omitempty"`
+	Role     string `yaml:"role,omitempty"`    // e.g. Compute, Management, Application
+	SubRole  string `yaml:"subrole,omitempty"` // e.g. Master, Worker, UAN
+	// Groups are free-form group or label names, e.g. a partition or slurm pool.
+	Groups []string `yaml:"groups,omitempty"`
+	// Interfaces lists every known network interface, including the primary one.
+	Interfaces []Interface `yaml:"interfaces,omitempty"`
+	// BMC is the xname of the BMC that manages a node.
+	BMC string `yaml:"bmc,omitempty"`
+	// Annotations hold arbitrary site-specific key/value data.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Interface is one named network interface of a BMC or node.
+type Interface struct {
+	Name    string `yaml:"name"`
+	MAC     string `yaml:"mac,omitempty"`
+	IP      string `yaml:"ip,omitempty"`
+	Network string `yaml:"network,omitempty"` // name of the network the interface is on, e.g. nmn, hsn
 }
 
-// FileFormat is the root YAML structure with bmcs and nodes.
+// FileFormat is the root YAML structure with bmcs and nodes. APIVersion and Kind identify
+// the schema; they are empty in documents written before the header existed and are set by
+// Marshal.
 type FileFormat struct {
-	BMCs  []Entry `yaml:"bmcs"`
-	Nodes []Entry `yaml:"nodes"`
+	APIVersion string  `yaml:"apiVersion,omitempty"`
+	Kind       string  `yaml:"kind,omitempty"`
+	BMCs       []Entry `yaml:"bmcs"`
+	Nodes      []Entry `yaml:"nodes"`
+}
+
+// NIDHostname returns the conventional hostname for a node ID, e.g. 7 -> nid000007.
+func NIDHostname(nid int) string {
+	return fmt.Sprintf("nid%06d", nid)
 }
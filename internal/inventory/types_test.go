@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMinimalFileRoundTrips(t *testing.T) {
+	minimal := `bmcs:
+    - xname: x9000c1s0b0
+      mac: "02:23:28:01:30:00"
+      ip: 192.168.100.1
+nodes:
+    - xname: x9000c1s0b0n0
+      mac: aa:bb:cc:dd:ee:01
+      ip: 10.42.0.1
+`
+	var doc FileFormat
+	if err := yaml.Unmarshal([]byte(minimal), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Nodes[0].NID != 0 || doc.Nodes[0].Interfaces != nil {
+		t.Fatalf("unexpected extended fields: %+v", doc.Nodes[0])
+	}
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != minimal {
+		t.Fatalf("minimal file changed on round trip:\n%s", out)
+	}
+}
+
+func TestExtendedFields(t *testing.T) {
+	src := `nodes:
+    - xname: x9000c1s0b0n0
+      mac: aa:bb:cc:dd:ee:01
+      ip: 10.42.0.1
+      nid: 1
+      hostname: nid000001
+      role: Compute
+      subrole: Worker
+      groups: [batch, gpu]
+      interfaces:
+        - name: hsn0
+          mac: 02:00:00:00:00:01
+          ip: 10.150.0.1
+          network: hsn
+      bmc: x9000c1s0b0
+      annotations:
+        rack: r12
+`
+	var doc FileFormat
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	n := doc.Nodes[0]
+	if n.NID != 1 || n.SubRole != "Worker" || len(n.Groups) != 2 || n.Interfaces[0].Network != "hsn" || n.BMC != "x9000c1s0b0" || n.Annotations["rack"] != "r12" {
+		t.Fatalf("extended fields not loaded: %+v", n)
+	}
+	if NIDHostname(42) != "nid000042" {
+		t.Errorf("NIDHostname(42) = %s", NIDHostname(42))
+	}
+}
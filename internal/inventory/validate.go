@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bootstrap/internal/xname"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity tells whether a Problem makes an inventory unusable.
+type Severity string
+
+// Problem severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is one finding of Validate.
+type Problem struct {
+	Line     int // 1-based line in the YAML file; 0 when unknown
+	Severity Severity
+	Path     string // e.g. nodes[3].ip
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d: %s: %s: %s", p.Line, p.Severity, p.Path, p.Message)
+}
+
+// HasErrors reports whether any problem has SeverityError.
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateOptions enables the optional checks of Validate.
+type ValidateOptions struct {
+	// BMCSubnet and NodeSubnet are CIDRs the bmcs[] and nodes[] IPs must be in. Empty
+	// skips the check.
+	BMCSubnet  string
+	NodeSubnet string
+}
+
+var (
+	xnamePattern = regexp.MustCompile(`^x\d+(c\d+((s|r)\d+(b\d+(n\d+)?)?|b\d+)?)?$`)
+	bmcPattern   = regexp.MustCompile(`b\d+$`)
+	nodePattern  = regexp.MustCompile(`s\d+b\d+n\d+$`)
+)
+
+// Validate checks an inventory document and returns every problem found, ordered by line:
+// malformed xnames, MACs and IPs, duplicate xnames, IPs, MACs, NIDs and hostnames, nodes
+// whose parent BMC is not in bmcs[], unknown fields, and IPs outside the subnets in opts.
+// It returns an error only when the document cannot be parsed at all.
+func Validate(raw []byte, opts ValidateOptions) ([]Problem, error) {
+	var bmcNet, nodeNet *net.IPNet
+	var err error
+	if opts.BMCSubnet != "" {
+		if _, bmcNet, err = net.ParseCIDR(opts.BMCSubnet); err != nil {
+			return nil, fmt.Errorf("bmc subnet: %w", err)
+		}
+	}
+	if opts.NodeSubnet != "" {
+		if _, nodeNet, err = net.ParseCIDR(opts.NodeSubnet); err != nil {
+			return nil, fmt.Errorf("node subnet: %w", err)
+		}
+	}
+	doc, _, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, err
+	}
+
+	v := &validator{lines: map[string]int{}}
+	v.index(&root, "")
+	v.unknownFields(&root)
+
+	seen := map[string]map[string]string{} // kind -> value -> path of first use
+	dup := func(kind, value, path string) {
+		if seen[kind] == nil {
+			seen[kind] = map[string]string{}
+		}
+		if first, ok := seen[kind][value]; ok {
+			v.add(SeverityError, path, "duplicate %s %s (also %s, line %d)", kind, value, first, v.line(first))
+			return
+		}
+		seen[kind][value] = path
+	}
+
+	bmcs := map[string]bool{}
+	for _, b := range doc.BMCs {
+		bmcs[b.Xname] = true
+	}
+	check := func(list string, entries []Entry, subnet *net.IPNet) {
+		for i, e := range entries {
+			path := fmt.Sprintf("%s[%d]", list, i)
+			switch {
+			case e.Xname == "":
+				v.add(SeverityError, path, "xname is required")
+			case !xnamePattern.MatchString(e.Xname):
+				v.add(SeverityError, path+".xname", "malformed xname %q", e.Xname)
+			case list == "bmcs" && !bmcPattern.MatchString(e.Xname):
+				v.add(SeverityWarning, path+".xname", "%s is not a BMC xname", e.Xname)
+			case list == "nodes" && !nodePattern.MatchString(e.Xname):
+				v.add(SeverityWarning, path+".xname", "%s is not a node xname", e.Xname)
+			}
+			if e.Xname != "" {
+				dup("xname", e.Xname, path+".xname")
+			}
+
+			// An address may appear both as the primary one and in interfaces[]; only
+			// repeats across entries or interfaces are duplicates.
+			macs, ips := map[string]bool{}, map[string]bool{}
+			addresses := func(path, mac, ip string, within *net.IPNet) {
+				if mac != "" {
+					if hw, err := net.ParseMAC(mac); err != nil {
+						v.add(SeverityError, path+".mac", "malformed MAC %q", mac)
+					} else if !macs[hw.String()] {
+						macs[hw.String()] = true
+						dup("MAC", hw.String(), path+".mac")
+					}
+				}
+				if ip == "" {
+					return
+				}
+				a := net.ParseIP(ip)
+				if a == nil {
+					// host:port is an endpoint reached through a proxy or port forward,
+					// which several BMCs may share; it is not an address to check.
+					if host, port, err := net.SplitHostPort(ip); err == nil && net.ParseIP(host) != nil && port != "" {
+						return
+					}
+					v.add(SeverityError, path+".ip", "malformed IP %q", ip)
+					return
+				}
+				if within != nil && !within.Contains(a) {
+					v.add(SeverityError, path+".ip", "%s is outside %s", ip, within)
+				}
+				if !ips[a.String()] {
+					ips[a.String()] = true
+					dup("IP", a.String(), path+".ip")
+				}
+			}
+			addresses(path, e.MAC, e.IP, subnet)
+			names := map[string]bool{}
+			for j, iface := range e.Interfaces {
+				ipath := fmt.Sprintf("%s.interfaces[%d]", path, j)
+				if iface.Name == "" {
+					v.add(SeverityError, ipath, "interface name is required")
+				} else if names[iface.Name] {
+					v.add(SeverityError, ipath+".name", "duplicate interface name %s", iface.Name)
+				}
+				names[iface.Name] = true
+				addresses(ipath, iface.MAC, iface.IP, nil)
+			}
+
+			if e.NID < 0 {
+				v.add(SeverityError, path+".nid", "nid must not be negative")
+			} else if e.NID > 0 {
+				dup("nid", strconv.Itoa(e.NID), path+".nid")
+			}
+			if e.Hostname != "" {
+				dup("hostname", e.Hostname, path+".hostname")
+			}
+			if list == "nodes" {
+				parent := e.BMC
+				if parent == "" {
+					parent = xname.NodeToBMC(e.Xname)
+				}
+				if parent != "" && !bmcs[parent] {
+					v.add(SeverityError, path+".bmc", "parent BMC %s is not in bmcs[]", parent)
+				}
+			}
+		}
+	}
+	check("bmcs", doc.BMCs, bmcNet)
+	check("nodes", doc.Nodes, nodeNet)
+
+	sort.SliceStable(v.problems, func(i, j int) bool { return v.problems[i].Line < v.problems[j].Line })
+	return v.problems, nil
+}
+
+type validator struct {
+	lines    map[string]int
+	problems []Problem
+}
+
+// index records the line of every mapping value and sequence item under path.
+func (v *validator) index(n *yaml.Node, path string) {
+	if n.Kind == yaml.DocumentNode {
+		for _, c := range n.Content {
+			v.index(c, path)
+		}
+		return
+	}
+	v.lines[path] = n.Line
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			v.index(n.Content[i+1], key)
+			v.lines[key] = n.Content[i].Line
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			v.index(c, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// line returns the line of path or, when the path is not in the file (e.g. a field
+// filled in by a schema upgrade), of its closest enclosing element.
+func (v *validator) line(path string) int {
+	for path != "" {
+		if l, ok := v.lines[path]; ok {
+			return l
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return 0
+}
+
+func (v *validator) add(sev Severity, path, format string, args ...any) {
+	v.problems = append(v.problems, Problem{Line: v.line(path), Severity: sev, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// unknownFields warns about keys that are not part of the schema, which are most likely
+// typos and would be dropped when the file is rewritten.
+func (v *validator) unknownFields(root *yaml.Node) {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	entry, iface := yamlFields(Entry{}), yamlFields(Interface{})
+	var walk func(n *yaml.Node, path string, known map[string]bool, children func(key string) map[string]bool)
+	walk = func(n *yaml.Node, path string, known map[string]bool, children func(key string) map[string]bool) {
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			kpath := key.Value
+			if path != "" {
+				kpath = path + "." + key.Value
+			}
+			if !known[key.Value] {
+				v.add(SeverityWarning, kpath, "unknown field %s", key.Value)
+				continue
+			}
+			if sub := children(key.Value); sub != nil && val.Kind == yaml.SequenceNode {
+				for j, item := range val.Content {
+					walk(item, fmt.Sprintf("%s[%d]", kpath, j), sub, func(k string) map[string]bool {
+						if k == "interfaces" {
+							return iface
+						}
+						return nil
+					})
+				}
+			}
+		}
+	}
+	walk(root, "", yamlFields(FileFormat{}), func(k string) map[string]bool {
+		if k == "bmcs" || k == "nodes" {
+			return entry
+		}
+		return nil
+	})
+}
+
+// yamlFields returns the YAML keys of a struct type.
+func yamlFields(v any) map[string]bool {
+	t := reflect.TypeOf(v)
+	keys := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		keys[name] = true
+	}
+	return keys
+}
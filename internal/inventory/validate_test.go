@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsProblemsWithLines(t *testing.T) {
+	src := `apiVersion: bootstrap.openchami.io/v1
+kind: Inventory
+bmcs:
+    - xname: x9000c1s0b0
+      mac: "02:23:28:01:30:00"
+      ip: 192.168.100.1
+    - xname: x9000c1s0b1
+      mac: 02:23:28:01:30:00
+      ip: 192.168.200.2
+nodes:
+    - xname: x9000c1s0b0n0
+      mac: aa:bb:cc:dd:ee:01
+      ip: 10.42.0.1
+      interfaces:
+        - name: eth0
+          mac: aa:bb:cc:dd:ee:01
+          ip: 10.42.0.1
+      bmc: x9000c1s0b0
+    - xname: x9000c1s9b0n0
+      ip: 10.42.0.1
+      hostnme: nid000002
+    - xname: bogus
+      mac: not-a-mac
+`
+	problems, err := Validate([]byte(src), ValidateOptions{BMCSubnet: "192.168.100.0/24", NodeSubnet: "10.42.0.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"8: error: bmcs[1].mac: duplicate MAC 02:23:28:01:30:00 (also bmcs[0].mac, line 5)",
+		"9: error: bmcs[1].ip: 192.168.200.2 is outside 192.168.100.0/24",
+		"19: error: nodes[1].bmc: parent BMC x9000c1s9b0 is not in bmcs[]",
+		"20: error: nodes[1].ip: duplicate IP 10.42.0.1 (also nodes[0].ip, line 13)",
+		"21: warning: nodes[1].hostnme: unknown field hostnme",
+		"22: error: nodes[2].xname: malformed xname \"bogus\"",
+		"23: error: nodes[2].mac: malformed MAC \"not-a-mac\"",
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !HasErrors(problems) {
+		t.Error("HasErrors = false")
+	}
+}
+
+func TestValidateCleanInventory(t *testing.T) {
+	src := `bmcs:
+    - xname: x9000c1s0b0
+      ip: 192.168.100.1
+    - xname: x9000c1s1b0
+      ip: 127.0.0.1:8443
+    - xname: x9000c1s2b0
+      ip: 127.0.0.1:8443
+nodes:
+    - xname: x9000c1s0b0n0
+      nid: 1
+      hostname: nid000001
+      role: Compute
+`
+	problems, err := Validate([]byte(src), ValidateOptions{})
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("problems=%v err=%v", problems, err)
+	}
+	if _, err := Validate([]byte("bmcs: [\n"), ValidateOptions{}); err == nil {
+		t.Error("expected a parse error")
+	}
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package journal records firmware rollouts as an append-only JSON lines file, one entry
+// per host event, so an interrupted rollout can be resumed and reported on later.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Events recorded for a host.
+const (
+	EventStart  = "start"
+	EventFinish = "finish"
+	EventVerify = "verify" // outcome of --verify after a triggered update
+)
+
+// Results recorded on finish entries.
+const (
+	ResultTriggered = "triggered" // SimpleUpdate was accepted by the BMC
+	ResultSkipped   = "skipped"   // the host needed no update
+	ResultFailed    = "failed"
+
+	// Verification outcomes
+	ResultUpdated     = "updated"
+	ResultUnchanged   = "unchanged"
+	ResultRolledBack  = "rolled-back"
+	ResultUnreachable = "unreachable"
+)
+
+// Entry is one line of the journal.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	Host    string    `json:"host"`
+	Xname   string    `json:"xname,omitempty"`
+	Image   string    `json:"image,omitempty"`
+	Targets []string  `json:"targets,omitempty"`
+	TaskURI string    `json:"task_uri,omitempty"`
+	Result  string    `json:"result,omitempty"`
+	Version string    `json:"version,omitempty"` // version observed on the host's targets
+	Error   string    `json:"error,omitempty"`
+}
+
+// Writer appends entries to a journal file. It is safe for concurrent use.
+type Writer struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Open opens path for appending, creating it if needed. A truncated final line left by an
+// interrupted run is terminated so that new entries start on a line of their own.
+func Open(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := f.Stat(); err == nil && fi.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, fi.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := f.Write([]byte{'\n'}); err != nil {
+				f.Close() //nolint:errcheck
+				return nil, err
+			}
+		}
+	}
+	return &Writer{f: f}, nil
+}
+
+// Record appends e, stamping the current time if e.Time is zero. Each entry is synced to
+// disk so that it survives the process being killed.
+func (w *Writer) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return w.f.Sync()
+}
+
+// Close closes the journal file.
+func (w *Writer) Close() error { return w.f.Close() }
+
+// Read parses the journal at path. Lines that are not valid JSON, such as a partial line
+// left by a process killed mid-write, are skipped and counted.
+func Read(path string) (entries []Entry, skipped int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close() //nolint:errcheck
+	return parse(f)
+}
+
+func parse(r io.Reader) ([]Entry, int, error) {
+	var entries []Entry
+	skipped := 0
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			skipped++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, skipped, sc.Err()
+}
+
+// HostState is the latest known state of one host in a journal.
+type HostState struct {
+	Host     string
+	Xname    string
+	Started  time.Time
+	Finished time.Time // zero while the update is in flight
+	Last     Entry
+}
+
+// InFlight reports whether the host was started but never finished.
+func (s HostState) InFlight() bool { return s.Finished.IsZero() }
+
+// Completed reports whether the host finished without failing, and was verified as updated
+// if it was verified at all.
+func (s HostState) Completed() bool {
+	switch s.Last.Result {
+	case ResultTriggered, ResultSkipped, ResultUpdated:
+		return !s.InFlight()
+	}
+	return false
+}
+
+// State returns the host's state for reports: in-flight or the last result.
+func (s HostState) State() string {
+	if s.InFlight() {
+		return "in-flight"
+	}
+	return s.Last.Result
+}
+
+// Summarize folds entries into the latest state per host, considering only entries for
+// image when it is non-empty. Hosts are returned sorted by xname, then host.
+func Summarize(entries []Entry, image string) []HostState {
+	byHost := map[string]*HostState{}
+	for _, e := range entries {
+		if image != "" && e.Image != image {
+			continue
+		}
+		s := byHost[e.Host]
+		if s == nil {
+			s = &HostState{Host: e.Host}
+			byHost[e.Host] = s
+		}
+		if e.Xname != "" {
+			s.Xname = e.Xname
+		}
+		switch e.Event {
+		case EventStart:
+			s.Started, s.Finished = e.Time, time.Time{}
+		case EventFinish:
+			s.Finished = e.Time
+		case EventVerify:
+			// Keep the task URI of the update being verified
+			if e.TaskURI == "" {
+				e.TaskURI = s.Last.TaskURI
+			}
+		}
+		s.Last = e
+	}
+	out := make([]HostState, 0, len(byHost))
+	for _, s := range byHost {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Xname != out[j].Xname {
+			return out[i].Xname < out[j].Xname
+		}
+		return out[i].Host < out[j].Host
+	})
+	return out
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndSummarize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	w, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []Entry{
+		{Event: EventStart, Host: "10.0.0.2", Xname: "x1c0s1b0", Image: "img"},
+		{Event: EventStart, Host: "10.0.0.1", Xname: "x1c0s0b0", Image: "img"},
+		{Event: EventFinish, Host: "10.0.0.1", Xname: "x1c0s0b0", Image: "img", Result: ResultTriggered, TaskURI: "/redfish/v1/TaskService/Tasks/1"},
+		{Event: EventStart, Host: "10.0.0.3", Image: "img"},
+		{Event: EventFinish, Host: "10.0.0.3", Image: "img", Result: ResultFailed, Error: "boom"},
+		{Event: EventStart, Host: "10.0.0.4", Image: "other"},
+		{Event: EventVerify, Host: "10.0.0.1", Xname: "x1c0s0b0", Image: "img", Result: ResultUpdated, Version: "2.0"},
+	} {
+		if err := w.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a process killed mid-write
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString(`{"event":"sta`) //nolint:errcheck
+	f.Close()                      //nolint:errcheck
+
+	entries, skipped, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 7 || skipped != 1 {
+		t.Fatalf("expected 7 entries and 1 skipped line, got %d and %d", len(entries), skipped)
+	}
+
+	// Appending after the truncated line must not corrupt the journal
+	w, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Record(Entry{Event: EventFinish, Host: "10.0.0.4", Image: "other", Result: ResultSkipped}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close() //nolint:errcheck
+	if entries, skipped, err = Read(path); err != nil || len(entries) != 8 || skipped != 1 {
+		t.Fatalf("expected 8 entries and 1 skipped line after appending, got %d, %d, %v", len(entries), skipped, err)
+	}
+
+	states := Summarize(entries, "img")
+	if len(states) != 3 {
+		t.Fatalf("expected 3 hosts for img, got %d", len(states))
+	}
+	// Sorted by xname; the host without an xname comes first
+	want := []struct {
+		host, state string
+		completed   bool
+	}{
+		{"10.0.0.3", ResultFailed, false},
+		{"10.0.0.1", ResultUpdated, true},
+		{"10.0.0.2", "in-flight", false},
+	}
+	for i, w := range want {
+		s := states[i]
+		if s.Host != w.host || s.State() != w.state || s.Completed() != w.completed {
+			t.Errorf("state %d = %s %s completed=%v, want %s %s %v", i, s.Host, s.State(), s.Completed(), w.host, w.state, w.completed)
+		}
+	}
+	if states[1].Last.TaskURI == "" {
+		t.Error("expected task URI to be kept")
+	}
+}
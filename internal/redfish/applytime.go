@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bootstrap/internal/diag"
+)
+
+// Redfish OperationApplyTime values accepted for SimpleUpdate.
+const (
+	ApplyImmediate                  = "Immediate"
+	ApplyOnReset                    = "OnReset"
+	ApplyAtMaintenanceWindowStart   = "AtMaintenanceWindowStart"
+	ApplyInMaintenanceWindowOnReset = "InMaintenanceWindowOnReset"
+)
+
+// ApplyTimeOptions controls when a BMC applies an image delivered by SimpleUpdate.
+// The zero value leaves the decision to the BMC.
+type ApplyTimeOptions struct {
+	ApplyTime      string
+	WindowStart    time.Time
+	WindowDuration time.Duration
+	// SetPushURIApplyTime allows setting the apply time through HttpPushUriOptions on BMCs
+	// whose SimpleUpdate has no per-request apply time. That setting is BMC-wide and stays
+	// in effect for later updates, including those started by other tools.
+	SetPushURIApplyTime bool
+}
+
+// ParseApplyTime validates an apply time value, matching case-insensitively, and
+// returns its canonical Redfish spelling.
+func ParseApplyTime(s string) (string, error) {
+	for _, v := range []string{ApplyImmediate, ApplyOnReset, ApplyAtMaintenanceWindowStart, ApplyInMaintenanceWindowOnReset} {
+		if strings.EqualFold(s, v) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("unknown apply time %q (use Immediate|OnReset|AtMaintenanceWindowStart|InMaintenanceWindowOnReset)", s)
+}
+
+// usesWindow reports whether the apply time depends on a maintenance window.
+func (o ApplyTimeOptions) usesWindow() bool {
+	return o.ApplyTime == ApplyAtMaintenanceWindowStart || o.ApplyTime == ApplyInMaintenanceWindowOnReset
+}
+
+func (o ApplyTimeOptions) window() map[string]any {
+	w := map[string]any{}
+	if !o.WindowStart.IsZero() {
+		w["MaintenanceWindowStartTime"] = o.WindowStart.UTC().Format(time.RFC3339)
+	}
+	if o.WindowDuration > 0 {
+		w["MaintenanceWindowDurationInSeconds"] = int(o.WindowDuration.Seconds())
+	}
+	return w
+}
+
+// ErrPushURIApplyTime is returned when the apply time could only be set through the
+// BMC-wide HttpPushUriOptions and ApplyTimeOptions.SetPushURIApplyTime is not set.
+var ErrPushURIApplyTime = errors.New("apply time can only be set through the BMC-wide UpdateService HttpPushUriOptions")
+
+type rfOperationApplyTimeSupport struct {
+	SupportedValues           []string `json:"SupportedValues"`
+	MaintenanceWindowResource struct {
+		OID string `json:"@odata.id"`
+	} `json:"MaintenanceWindowResource"`
+}
+
+type rfUpdateServiceApplyTime struct {
+	Actions struct {
+		SimpleUpdate struct {
+			ApplyTimeSupport *rfOperationApplyTimeSupport `json:"@Redfish.OperationApplyTimeSupport"`
+		} `json:"#UpdateService.SimpleUpdate"`
+	} `json:"Actions"`
+	HTTPPushURIOptions *struct {
+		HTTPPushURIApplyTime struct {
+			ApplyTime string `json:"ApplyTime"`
+		} `json:"HttpPushUriApplyTime"`
+	} `json:"HttpPushUriOptions"`
+}
+
+func containsFold(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTimePayload adds the apply time to a SimpleUpdate payload, or configures it through
+// HttpPushUriOptions, depending on what the UpdateService advertises. HttpPushUriOptions is
+// only changed with opts.SetPushURIApplyTime.
+func (c *client) applyTimePayload(ctx context.Context, payload map[string]any, opts ApplyTimeOptions) error {
+	if opts.ApplyTime == "" {
+		return nil
+	}
+	var us rfUpdateServiceApplyTime
+	if err := c.get(ctx, "/UpdateService", &us); err != nil {
+		return fmt.Errorf("read apply time support: %w", err)
+	}
+
+	if sup := us.Actions.SimpleUpdate.ApplyTimeSupport; sup != nil {
+		if len(sup.SupportedValues) > 0 && !containsFold(sup.SupportedValues, opts.ApplyTime) {
+			return fmt.Errorf("apply time %s not supported by SimpleUpdate (supported: %s)",
+				opts.ApplyTime, strings.Join(sup.SupportedValues, ", "))
+		}
+		payload["@Redfish.OperationApplyTime"] = opts.ApplyTime
+		if opts.usesWindow() && len(opts.window()) > 0 {
+			if res := sup.MaintenanceWindowResource.OID; res != "" {
+				// The window lives on the advertised resource rather than in the action body
+				return c.patch(ctx, res, map[string]any{"@Redfish.MaintenanceWindow": opts.window()})
+			}
+			payload["@Redfish.MaintenanceWindow"] = opts.window()
+		}
+		return nil
+	}
+
+	if us.HTTPPushURIOptions != nil {
+		if !opts.SetPushURIApplyTime {
+			return fmt.Errorf("%s: %w (currently %s), which is not restored and applies to later updates too",
+				opts.ApplyTime, ErrPushURIApplyTime, us.HTTPPushURIOptions.HTTPPushURIApplyTime.ApplyTime)
+		}
+		applyTime := map[string]any{"ApplyTime": opts.ApplyTime}
+		if opts.usesWindow() {
+			for k, v := range opts.window() {
+				applyTime[k] = v
+			}
+		}
+		diag.Logf("configuring apply time %s via HttpPushUriOptions", opts.ApplyTime)
+		return c.patch(ctx, "/UpdateService", map[string]any{
+			"HttpPushUriOptions": map[string]any{"HttpPushUriApplyTime": applyTime},
+		})
+	}
+
+	// Nothing advertised: send the annotation and let the BMC reject it if unsupported
+	diag.Logf("UpdateService does not advertise apply time support; sending @Redfish.OperationApplyTime")
+	payload["@Redfish.OperationApplyTime"] = opts.ApplyTime
+	if opts.usesWindow() && len(opts.window()) > 0 {
+		payload["@Redfish.MaintenanceWindow"] = opts.window()
+	}
+	return nil
+}
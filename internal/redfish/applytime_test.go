@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// applyTimeServer serves an UpdateService document and records SimpleUpdate and PATCH bodies.
+func applyTimeServer(t *testing.T, updateService string, posted, patched *map[string]any) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/redfish/v1/UpdateService":
+			_, _ = w.Write([]byte(updateService))
+		case r.Method == "POST" && r.URL.Path == "/redfish/v1/UpdateService/Actions/SimpleUpdate":
+			_ = json.NewDecoder(r.Body).Decode(posted)
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == "PATCH":
+			_ = json.NewDecoder(r.Body).Decode(patched)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestSimpleUpdateTask_OperationApplyTime(t *testing.T) {
+	var posted, patched map[string]any
+	ts := applyTimeServer(t, `{
+		"Actions": {"#UpdateService.SimpleUpdate": {
+			"target": "/redfish/v1/UpdateService/Actions/SimpleUpdate",
+			"@Redfish.OperationApplyTimeSupport": {"SupportedValues": ["Immediate", "OnReset", "AtMaintenanceWindowStart"]}
+		}}
+	}`, &posted, &patched)
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	start := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	_, err := SimpleUpdateTask(context.Background(), host, "u", "p", true, 5*time.Second, "http://example.com/fw.bin",
+		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false,
+		ApplyTimeOptions{ApplyTime: ApplyAtMaintenanceWindowStart, WindowStart: start, WindowDuration: time.Hour})
+	if err != nil {
+		t.Fatalf("SimpleUpdateTask failed: %v", err)
+	}
+	if posted["@Redfish.OperationApplyTime"] != ApplyAtMaintenanceWindowStart {
+		t.Errorf("missing apply time annotation in payload: %v", posted)
+	}
+	win, _ := posted["@Redfish.MaintenanceWindow"].(map[string]any)
+	if win["MaintenanceWindowStartTime"] != "2026-01-02T03:00:00Z" || win["MaintenanceWindowDurationInSeconds"] != float64(3600) {
+		t.Errorf("unexpected maintenance window: %v", win)
+	}
+	if patched != nil {
+		t.Errorf("did not expect a PATCH, got %v", patched)
+	}
+}
+
+func TestSimpleUpdateTask_UnsupportedApplyTime(t *testing.T) {
+	var posted, patched map[string]any
+	ts := applyTimeServer(t, `{
+		"Actions": {"#UpdateService.SimpleUpdate": {
+			"@Redfish.OperationApplyTimeSupport": {"SupportedValues": ["Immediate"]}
+		}}
+	}`, &posted, &patched)
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	_, err := SimpleUpdateTask(context.Background(), host, "u", "p", true, 5*time.Second, "http://example.com/fw.bin",
+		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false,
+		ApplyTimeOptions{ApplyTime: ApplyOnReset})
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("expected unsupported apply time error, got: %v", err)
+	}
+	if posted != nil {
+		t.Errorf("SimpleUpdate should not be posted, got %v", posted)
+	}
+}
+
+func TestSimpleUpdateTask_HttpPushUriOptions(t *testing.T) {
+	var posted, patched map[string]any
+	ts := applyTimeServer(t, `{"HttpPushUriOptions": {"HttpPushUriApplyTime": {"ApplyTime": "Immediate"}}}`, &posted, &patched)
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	_, err := SimpleUpdateTask(context.Background(), host, "u", "p", true, 5*time.Second, "http://example.com/fw.bin",
+		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false,
+		ApplyTimeOptions{ApplyTime: ApplyOnReset})
+	if !errors.Is(err, ErrPushURIApplyTime) || !strings.Contains(err.Error(), "(currently Immediate)") {
+		t.Fatalf("expected the BMC-wide setting to be refused without opt-in, got: %v", err)
+	}
+	if posted != nil || patched != nil {
+		t.Fatalf("nothing should be sent without opt-in, got POST %v PATCH %v", posted, patched)
+	}
+
+	_, err = SimpleUpdateTask(context.Background(), host, "u", "p", true, 5*time.Second, "http://example.com/fw.bin",
+		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false,
+		ApplyTimeOptions{ApplyTime: ApplyOnReset, SetPushURIApplyTime: true})
+	if err != nil {
+		t.Fatalf("SimpleUpdateTask failed: %v", err)
+	}
+	opts, _ := patched["HttpPushUriOptions"].(map[string]any)
+	at, _ := opts["HttpPushUriApplyTime"].(map[string]any)
+	if at["ApplyTime"] != ApplyOnReset {
+		t.Errorf("expected HttpPushUriApplyTime to be patched, got %v", patched)
+	}
+	if _, ok := posted["@Redfish.OperationApplyTime"]; ok {
+		t.Errorf("did not expect annotation in payload: %v", posted)
+	}
+}
+
+func TestParseApplyTime(t *testing.T) {
+	got, err := ParseApplyTime("onreset")
+	if err != nil || got != ApplyOnReset {
+		t.Fatalf("ParseApplyTime(onreset) = %q, %v", got, err)
+	}
+	if _, err := ParseApplyTime("Later"); err == nil {
+		t.Fatal("expected error for unknown apply time")
+	}
+}
+
+func TestFirmwareInventoryStaged(t *testing.T) {
+	tests := []struct {
+		name string
+		inv  FirmwareInventory
+		want bool
+	}{
+		{"enabled", FirmwareInventory{State: "Enabled"}, false},
+		{"standby offline", FirmwareInventory{State: "StandbyOffline"}, true},
+		{"awaiting activation", FirmwareInventory{State: "Enabled", Conditions: []FirmwareCondition{{MessageID: "Update.1.1.AwaitingActivation"}}}, true},
+		{"installing", FirmwareInventory{State: "Updating", Conditions: []FirmwareCondition{{Message: "Installing firmware"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.inv.Staged(); got != tt.want {
+				t.Errorf("Staged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -139,6 +139,16 @@ type rfTask struct {
 // be running firmware/update jobs. This is a best-effort heuristic that looks for running
 // TaskState values and checks Name/Message for update/firmware keywords.
 func GetActiveUpdateTasks(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]string, error) {
+	return updateTasksInState(ctx, host, user, pass, insecure, timeout, "running", "starting", "inprogress", "queued")
+}
+
+// GetPendingUpdateTasks returns the IDs of update tasks that are scheduled but not running,
+// e.g. images staged with an OnReset or maintenance-window apply time.
+func GetPendingUpdateTasks(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]string, error) {
+	return updateTasksInState(ctx, host, user, pass, insecure, timeout, "pending")
+}
+
+func updateTasksInState(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, states ...string) ([]string, error) {
 	c := newClient(host, user, pass, insecure, timeout)
 	var coll rfTaskCollection
 	if err := c.get(ctx, "/TaskService/Tasks", &coll); err != nil {
@@ -154,13 +164,13 @@ func GetActiveUpdateTasks(ctx context.Context, host, user, pass string, insecure
 		ts := strings.ToLower(t.TaskState)
 		name := strings.ToLower(t.Name)
 		msg := strings.ToLower(t.Message)
-		if ts == "running" || ts == "starting" || ts == "inprogress" || ts == "queued" {
+		if containsFold(states, ts) {
 			// If it looks like an update-related task, include it
 			if strings.Contains(name, "update") || strings.Contains(name, "firmware") || strings.Contains(msg, "update") || strings.Contains(msg, "firmware") {
 				out = append(out, t.ID)
 				continue
 			}
-			// If task state indicates running and name/message are empty-ish, include conservatively
+			// If task state matches and name/message are empty-ish, include conservatively
 			if name == "" && msg == "" {
 				out = append(out, t.ID)
 			}
@@ -208,6 +218,24 @@ func GetFirmwareInventory(ctx context.Context, host, user, pass string, insecure
 	return out, nil
 }
 
+// Staged reports whether the inventory entry holds an image that has been delivered but
+// not yet activated, e.g. because it was sent with an OnReset or maintenance-window apply time.
+func (f FirmwareInventory) Staged() bool {
+	if strings.EqualFold(f.State, "StandbyOffline") {
+		return true
+	}
+	for _, c := range f.Conditions {
+		if strings.Contains(c.MessageID, "AwaitingActivation") {
+			return true
+		}
+		m := strings.ToLower(c.Message)
+		if strings.Contains(m, "awaiting activation") || strings.Contains(m, "pending activation") || strings.Contains(m, "staged") {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *client) get(ctx context.Context, path string, v any) error {
 	path = c.resolvePath(path)
 	diag.Logf("GET %s", path)
@@ -225,45 +253,77 @@ func (c *client) get(ctx context.Context, path string, v any) error {
 	diag.Logf("GET %s -> %s", path, resp.Status)
 	if resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("redfish %s: %s: %s", path, resp.Status, strings.TrimSpace(string(b)))
+		return fmt.Errorf("redfish %s: %w: %s", path, &statusError{code: resp.StatusCode, status: resp.Status}, strings.TrimSpace(string(b)))
 	}
 	return json.NewDecoder(resp.Body).Decode(v)
 }
 
+// statusError is the HTTP status of a failed GET, so callers can tell a resource that is
+// gone from one that could not be read.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string { return e.status }
+
+// isGone reports whether err is a GET answered with 404 Not Found or 410 Gone.
+func isGone(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && (se.code == http.StatusNotFound || se.code == http.StatusGone)
+}
+
 func (c *client) post(ctx context.Context, path string, body any) error {
+	_, err := c.postTask(ctx, path, body)
+	return err
+}
+
+// postTask is post for actions that may start a Redfish task. It returns the task URI from
+// the Location header or the @odata.id of a returned Task, or "" when there is none.
+func (c *client) postTask(ctx context.Context, path string, body any) (string, error) {
 	path = c.resolvePath(path)
 	b, err := json.Marshal(body)
 	if err != nil {
-		return err
+		return "", err
 	}
 	diag.Logf("POST %s", path)
 	req, err := http.NewRequestWithContext(ctx, "POST", path, strings.NewReader(string(b)))
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.SetBasicAuth(c.user, c.pass)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close() // nolint:errcheck
 	diag.Logf("POST %s -> %s", path, resp.Status)
+	rb, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 300 {
-		rb, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("redfish POST %s: %s: %s", path, resp.Status, strings.TrimSpace(string(rb)))
+		return "", fmt.Errorf("redfish POST %s: %s: %s", path, resp.Status, strings.TrimSpace(string(rb)))
 	}
-	return nil
+	if loc := resp.Header.Get("Location"); loc != "" {
+		return loc, nil
+	}
+	var task struct {
+		ID string `json:"@odata.id"`
+	}
+	if json.Unmarshal(rb, &task) == nil && strings.Contains(task.ID, "/Task") {
+		return task.ID, nil
+	}
+	return "", nil
 }
 
 func (c *client) patch(ctx context.Context, path string, body any) error {
+	path = c.resolvePath(path)
 	b, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 	diag.Logf("PATCH %s", path)
-	req, err := http.NewRequestWithContext(ctx, "PATCH", c.base+path, strings.NewReader(string(b)))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", path, strings.NewReader(string(b)))
 	if err != nil {
 		return err
 	}
@@ -294,6 +354,21 @@ func (c *client) firstSystemPath(ctx context.Context) (string, error) {
 	return coll.Members[0].OID, nil
 }
 
+// ListFirmwareInventory returns the @odata.id of every member of the UpdateService
+// FirmwareInventory collection.
+func ListFirmwareInventory(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]string, error) {
+	c := newClient(host, user, pass, insecure, timeout)
+	var coll rfCollection
+	if err := c.get(ctx, "/UpdateService/FirmwareInventory", &coll); err != nil {
+		return nil, err
+	}
+	out := make([]string, len(coll.Members))
+	for i, m := range coll.Members {
+		out[i] = m.OID
+	}
+	return out, nil
+}
+
 func (c *client) listSystemPaths(ctx context.Context) ([]string, error) {
 	var coll rfCollection
 	if err := c.get(ctx, "/Systems", &coll); err != nil {
@@ -371,6 +446,14 @@ func isValidMAC(mac string) bool {
 type SystemMACs struct {
 	SystemPath string
 	MACs       []string
+	// Interfaces names each MAC in MACs by its EthernetInterface Id, in the same order.
+	Interfaces []NIC
+}
+
+// NIC is one EthernetInterface of a system.
+type NIC struct {
+	ID  string
+	MAC string
 }
 
 // DiscoverAllBootableMACs returns bootable MAC addresses for all systems on a BMC.
@@ -392,18 +475,21 @@ func DiscoverAllBootableMACs(ctx context.Context, host, user, pass string, insec
 
 		// collect bootable MACs, fallback to first valid MAC if none
 		macs := make([]string, 0, len(nics))
+		var ifaces []NIC
 		for _, nic := range nics {
 			if !isValidMAC(nic.MACAddress) {
 				continue
 			}
 			if isBootable(nic) {
 				macs = append(macs, strings.ToLower(nic.MACAddress))
+				ifaces = append(ifaces, NIC{ID: nic.ID, MAC: strings.ToLower(nic.MACAddress)})
 			}
 		}
 		if len(macs) == 0 {
 			for _, nic := range nics {
 				if isValidMAC(nic.MACAddress) {
 					macs = append(macs, strings.ToLower(nic.MACAddress))
+					ifaces = append(ifaces, NIC{ID: nic.ID, MAC: strings.ToLower(nic.MACAddress)})
 					break
 				}
 			}
@@ -413,6 +499,7 @@ func DiscoverAllBootableMACs(ctx context.Context, host, user, pass string, insec
 			result = append(result, SystemMACs{
 				SystemPath: sysPath,
 				MACs:       macs,
+				Interfaces: ifaces,
 			})
 		}
 	}
@@ -457,6 +544,14 @@ func DiscoverBootableMACs(ctx context.Context, host, user, pass string, insecure
 // transferProtocol is typically "HTTP" or "HTTPS".
 // If expectedVersion is provided and force is false, the update is skipped if any target already has that version.
 func SimpleUpdate(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool) error {
+	_, err := SimpleUpdateTask(ctx, host, user, pass, insecure, timeout, imageURI, targets, transferProtocol, expectedVersion, force, ApplyTimeOptions{})
+	return err
+}
+
+// SimpleUpdateTask is SimpleUpdate with an explicit apply time that also returns the URI of
+// the task the BMC started for the update, if it reported one. The apply time is sent as
+// @Redfish.OperationApplyTime or through HttpPushUriOptions, whichever the BMC advertises.
+func SimpleUpdateTask(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool, apply ApplyTimeOptions) (string, error) {
 	c := newClient(host, user, pass, insecure, timeout)
 
 	// Check current versions if expectedVersion is provided and not forcing
@@ -480,7 +575,7 @@ func SimpleUpdate(ctx context.Context, host, user, pass string, insecure bool, t
 		}
 
 		if allAtExpectedVersion && len(versionInfo) > 0 {
-			return fmt.Errorf("skipping update: all targets already at expected version %s\n%s",
+			return "", fmt.Errorf("skipping update: all targets already at expected version %s\n%s",
 				expectedVersion, strings.Join(versionInfo, "\n"))
 		}
 	}
@@ -490,9 +585,13 @@ func SimpleUpdate(ctx context.Context, host, user, pass string, insecure bool, t
 		"TransferProtocol": transferProtocol,
 		"Targets":          targets,
 	}
+	if err := c.applyTimePayload(ctx, payload, apply); err != nil {
+		return "", err
+	}
 	// Vendor path per provided examples
-	if err := c.post(ctx, "/UpdateService/Actions/SimpleUpdate", payload); err != nil {
-		return err
+	taskURI, err := c.postTask(ctx, "/UpdateService/Actions/SimpleUpdate", payload)
+	if err != nil {
+		return "", err
 	}
 
 	// Check firmware inventory status for any conditions/errors
@@ -516,10 +615,10 @@ func SimpleUpdate(ctx context.Context, host, user, pass string, insecure bool, t
 	}
 
 	if len(statusErrors) > 0 {
-		return fmt.Errorf("firmware update completed with warnings/errors:\n%s", strings.Join(statusErrors, "\n"))
+		return taskURI, fmt.Errorf("firmware update completed with warnings/errors:\n%s", strings.Join(statusErrors, "\n"))
 	}
 
-	return nil
+	return taskURI, nil
 }
 
 // SetAuthorizedKeys configures the SSH authorized keys on a BMC.
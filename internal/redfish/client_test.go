@@ -704,3 +704,42 @@ func TestSimpleUpdate_UpdateWhenDifferentVersion(t *testing.T) {
 		t.Error("expected SimpleUpdate POST to be called when version differs")
 	}
 }
+
+func TestSimpleUpdateTask_ReturnsTaskURI(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		body     string
+		want     string
+	}{
+		{name: "location header", location: "/redfish/v1/TaskService/TaskMonitors/7", want: "/redfish/v1/TaskService/TaskMonitors/7"},
+		{name: "task body", body: `{"@odata.id": "/redfish/v1/TaskService/Tasks/3", "TaskState": "Running"}`, want: "/redfish/v1/TaskService/Tasks/3"},
+		{name: "no task", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "POST" && r.URL.Path == "/redfish/v1/UpdateService/Actions/SimpleUpdate" {
+					if tt.location != "" {
+						w.Header().Set("Location", tt.location)
+					}
+					w.WriteHeader(http.StatusAccepted)
+					_, _ = w.Write([]byte(tt.body))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer server.Close()
+
+			host := server.URL[len("https://"):]
+			got, err := SimpleUpdateTask(context.Background(), host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
+				[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false, ApplyTimeOptions{})
+			if err != nil {
+				t.Fatalf("SimpleUpdateTask failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("task URI = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type rfManager struct {
+	ID           string `json:"Id"`
+	Manufacturer string `json:"Manufacturer"`
+	Model        string `json:"Model"`
+	Status       struct {
+		Health string `json:"Health"`
+		State  string `json:"State"`
+	} `json:"Status"`
+	Actions struct {
+		Reset struct {
+			Target string `json:"target"`
+		} `json:"#Manager.Reset"`
+	} `json:"Actions"`
+}
+
+// ManagerStatus is an exported, simplified representation of Manager.Status.
+type ManagerStatus struct {
+	Health string
+	State  string
+}
+
+// Ready reports whether the manager is Enabled with OK health.
+func (s ManagerStatus) Ready() bool {
+	return strings.EqualFold(s.State, "Enabled") && strings.EqualFold(s.Health, "OK")
+}
+
+func (c *client) firstManagerPath(ctx context.Context) (string, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/Managers", &coll); err != nil {
+		return "", err
+	}
+	if len(coll.Members) == 0 {
+		return "", errors.New("no managers reported by BMC")
+	}
+	return coll.Members[0].OID, nil
+}
+
+// ResetManager posts Manager.Reset with the given ResetType (e.g. GracefulRestart, ForceRestart)
+// to the first manager reported by the BMC.
+func ResetManager(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, resetType string) error {
+	c := newClient(host, user, pass, insecure, timeout)
+	mgrPath, err := c.firstManagerPath(ctx)
+	if err != nil {
+		return err
+	}
+	var mgr rfManager
+	if err := c.get(ctx, mgrPath, &mgr); err != nil {
+		return err
+	}
+	target := mgr.Actions.Reset.Target
+	if target == "" {
+		// Fall back to the standard action path when the manager does not advertise one
+		target = mgrPath + "/Actions/Manager.Reset"
+	}
+	return c.post(ctx, target, map[string]any{"ResetType": resetType})
+}
+
+// GetManagerStatus checks that the service root answers and returns the Status of the first manager.
+func GetManagerStatus(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) (ManagerStatus, error) {
+	c := newClient(host, user, pass, insecure, timeout)
+	var root map[string]any
+	if err := c.get(ctx, c.base, &root); err != nil {
+		return ManagerStatus{}, err
+	}
+	mgrPath, err := c.firstManagerPath(ctx)
+	if err != nil {
+		return ManagerStatus{}, err
+	}
+	var mgr rfManager
+	if err := c.get(ctx, mgrPath, &mgr); err != nil {
+		return ManagerStatus{}, err
+	}
+	return ManagerStatus{Health: mgr.Status.Health, State: mgr.Status.State}, nil
+}
+
+// WaitManagerReady polls the BMC every interval until its manager reports Enabled/OK
+// or ctx is done. Connection errors while the BMC is rebooting are expected and retried.
+func WaitManagerReady(ctx context.Context, host, user, pass string, insecure bool, timeout, interval time.Duration) error {
+	var last error
+	for {
+		st, err := GetManagerStatus(ctx, host, user, pass, insecure, timeout)
+		switch {
+		case err != nil:
+			last = err
+		case st.Ready():
+			return nil
+		default:
+			last = fmt.Errorf("manager status: state=%s health=%s", st.State, st.Health)
+		}
+		select {
+		case <-ctx.Done():
+			if last != nil {
+				return fmt.Errorf("%w (last: %v)", ctx.Err(), last)
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
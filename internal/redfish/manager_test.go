@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResetManager(t *testing.T) {
+	var gotType string
+	var gotPath string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/redfish/v1/Managers":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`))
+		case r.Method == "GET" && r.URL.Path == "/redfish/v1/Managers/BMC":
+			_, _ = w.Write([]byte(`{"Id":"BMC","Actions":{"#Manager.Reset":{"target":"/redfish/v1/Managers/BMC/Actions/Manager.Reset"}}}`))
+		case r.Method == "POST":
+			gotPath = r.URL.Path
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			gotType = body["ResetType"]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	if err := ResetManager(context.Background(), host, "u", "p", true, 2*time.Second, "GracefulRestart"); err != nil {
+		t.Fatalf("ResetManager failed: %v", err)
+	}
+	if gotPath != "/redfish/v1/Managers/BMC/Actions/Manager.Reset" {
+		t.Errorf("got POST path %q", gotPath)
+	}
+	if gotType != "GracefulRestart" {
+		t.Errorf("got ResetType %q, want GracefulRestart", gotType)
+	}
+}
+
+func TestWaitManagerReady(t *testing.T) {
+	// The manager reports Starting for the first few polls, then Enabled/OK.
+	var polls int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/redfish/v1":
+			_, _ = w.Write([]byte(`{"Id":"RootService"}`))
+		case "/redfish/v1/Managers":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`))
+		case "/redfish/v1/Managers/BMC":
+			if atomic.AddInt32(&polls, 1) < 3 {
+				_, _ = w.Write([]byte(`{"Id":"BMC","Status":{"State":"Starting","Health":"OK"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Id":"BMC","Status":{"State":"Enabled","Health":"OK"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitManagerReady(ctx, host, "u", "p", true, time.Second, 10*time.Millisecond); err != nil {
+		t.Fatalf("WaitManagerReady failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&polls); got != 3 {
+		t.Errorf("got %d manager polls, want 3", got)
+	}
+}
+
+func TestWaitManagerReadyTimeout(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	err := WaitManagerReady(ctx, host, "u", "p", true, time.Second, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "last:") {
+		t.Errorf("expected last error to be reported, got: %v", err)
+	}
+}
+
+func TestGetProducts(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/redfish/v1/Managers":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`))
+		case "/redfish/v1/Managers/BMC":
+			_, _ = w.Write([]byte(`{"Id":"BMC","Manufacturer":"HPE","Model":"Windom NC"}`))
+		case "/redfish/v1/Chassis":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Chassis/Blade0"}]}`))
+		case "/redfish/v1/Chassis/Blade0":
+			_, _ = w.Write([]byte(`{"Id":"Blade0","Manufacturer":"HPE","Model":"EX425"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	products, err := GetProducts(context.Background(), host, "u", "p", true, 2*time.Second)
+	if err != nil {
+		t.Fatalf("GetProducts failed: %v", err)
+	}
+	want := []Product{
+		{Source: "Manager", Path: "/redfish/v1/Managers/BMC", Manufacturer: "HPE", Model: "Windom NC"},
+		{Source: "Chassis", Path: "/redfish/v1/Chassis/Blade0", Manufacturer: "HPE", Model: "EX425"},
+	}
+	if len(products) != len(want) {
+		t.Fatalf("got %+v, want %+v", products, want)
+	}
+	for i := range want {
+		if products[i] != want[i] {
+			t.Errorf("products[%d] = %+v, want %+v", i, products[i], want[i])
+		}
+	}
+}
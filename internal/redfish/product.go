@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"time"
+)
+
+// Product identifies the hardware behind one Manager or Chassis resource.
+type Product struct {
+	Source       string // Manager or Chassis
+	Path         string
+	Manufacturer string
+	Model        string
+}
+
+// GetProducts returns the Manufacturer and Model of the first manager and of every chassis
+// reported by the BMC. Chassis are optional: a BMC without a Chassis collection only
+// returns its manager.
+func GetProducts(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]Product, error) {
+	c := newClient(host, user, pass, insecure, timeout)
+	mgrPath, err := c.firstManagerPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var mgr rfManager
+	if err := c.get(ctx, mgrPath, &mgr); err != nil {
+		return nil, err
+	}
+	out := []Product{{Source: "Manager", Path: mgrPath, Manufacturer: mgr.Manufacturer, Model: mgr.Model}}
+
+	var coll rfCollection
+	if err := c.get(ctx, "/Chassis", &coll); err != nil {
+		return out, nil //nolint:nilerr
+	}
+	for _, m := range coll.Members {
+		var ch struct {
+			Manufacturer string `json:"Manufacturer"`
+			Model        string `json:"Model"`
+		}
+		if err := c.get(ctx, m.OID, &ch); err != nil {
+			return nil, err
+		}
+		out = append(out, Product{Source: "Chassis", Path: m.OID, Manufacturer: ch.Manufacturer, Model: ch.Model})
+	}
+	return out, nil
+}
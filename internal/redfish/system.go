@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type rfSystem struct {
+	ID           string `json:"Id"`
+	PowerState   string `json:"PowerState"`
+	BootProgress struct {
+		LastState string `json:"LastState"`
+	} `json:"BootProgress"`
+	Actions struct {
+		Reset struct {
+			Target string `json:"target"`
+		} `json:"#ComputerSystem.Reset"`
+	} `json:"Actions"`
+}
+
+// SystemPower is the power and boot state of one ComputerSystem.
+type SystemPower struct {
+	Path         string
+	PowerState   string // On, Off, PoweringOn, PoweringOff, Paused
+	BootProgress string // BootProgress.LastState; empty when the BMC does not report it
+}
+
+// On reports whether the system is powered on.
+func (s SystemPower) On() bool { return strings.EqualFold(s.PowerState, "On") }
+
+// Off reports whether the system is powered off.
+func (s SystemPower) Off() bool { return strings.EqualFold(s.PowerState, "Off") }
+
+// Booted reports whether the system is on and has started its OS. Systems whose BMC does not
+// report BootProgress count as booted once they are on.
+func (s SystemPower) Booted() bool {
+	if !s.On() {
+		return false
+	}
+	switch s.BootProgress {
+	case "", "OSBootStarted", "OSRunning":
+		return true
+	}
+	return false
+}
+
+// GetSystemsPower returns the power and boot state of every ComputerSystem on the BMC.
+func GetSystemsPower(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]SystemPower, error) {
+	c := newClient(host, user, pass, insecure, timeout)
+	paths, err := c.listSystemPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SystemPower, 0, len(paths))
+	for _, p := range paths {
+		sp, err := c.systemPower(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sp)
+	}
+	return out, nil
+}
+
+// GetSystemPower returns the power and boot state of the ComputerSystem at sysPath.
+func GetSystemPower(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, sysPath string) (SystemPower, error) {
+	return newClient(host, user, pass, insecure, timeout).systemPower(ctx, sysPath)
+}
+
+func (c *client) systemPower(ctx context.Context, sysPath string) (SystemPower, error) {
+	var sys rfSystem
+	if err := c.get(ctx, sysPath, &sys); err != nil {
+		return SystemPower{}, err
+	}
+	return SystemPower{Path: sysPath, PowerState: sys.PowerState, BootProgress: sys.BootProgress.LastState}, nil
+}
+
+// ResetSystem posts ComputerSystem.Reset with the given ResetType (On, GracefulShutdown,
+// GracefulRestart, ForceOff, ...) to the system at sysPath.
+func ResetSystem(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, sysPath, resetType string) error {
+	c := newClient(host, user, pass, insecure, timeout)
+	var sys rfSystem
+	if err := c.get(ctx, sysPath, &sys); err != nil {
+		return err
+	}
+	target := sys.Actions.Reset.Target
+	if target == "" {
+		target = sysPath + "/Actions/ComputerSystem.Reset"
+	}
+	return c.post(ctx, target, map[string]any{"ResetType": resetType})
+}
+
+// WaitSystemPower polls the system at sysPath every interval until done reports true or ctx
+// is done, and returns the last state read. Read errors are retried.
+func WaitSystemPower(ctx context.Context, host, user, pass string, insecure bool, timeout, interval time.Duration, sysPath string, done func(SystemPower) bool) (SystemPower, error) {
+	c := newClient(host, user, pass, insecure, timeout)
+	var last SystemPower
+	var lastErr error
+	for {
+		sp, err := c.systemPower(ctx, sysPath)
+		if err == nil {
+			last, lastErr = sp, nil
+			if done(sp) {
+				return sp, nil
+			}
+		} else if ctx.Err() == nil {
+			// A poll cut short by ctx says nothing about the system
+			lastErr = err
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return last, fmt.Errorf("%w (last: %v)", ctx.Err(), lastErr)
+			}
+			return last, fmt.Errorf("%w (last: PowerState=%s BootProgress=%s)", ctx.Err(), last.PowerState, last.BootProgress)
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResetSystemAndWait(t *testing.T) {
+	var gotType, gotPath string
+	var polls int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/redfish/v1/Systems":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"}]}`))
+		case r.Method == "GET" && r.URL.Path == "/redfish/v1/Systems/Node0":
+			if gotType == "" || atomic.AddInt32(&polls, 1) < 3 {
+				_, _ = w.Write([]byte(`{"Id":"Node0","PowerState":"On","BootProgress":{"LastState":"OSRunning"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Id":"Node0","PowerState":"Off","BootProgress":{"LastState":"None"}}`))
+		case r.Method == "POST":
+			gotPath = r.URL.Path
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			gotType = body["ResetType"]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	ctx := context.Background()
+	systems, err := GetSystemsPower(ctx, host, "u", "p", true, 2*time.Second)
+	if err != nil {
+		t.Fatalf("GetSystemsPower failed: %v", err)
+	}
+	if len(systems) != 1 || !systems[0].Booted() || systems[0].Path != "/redfish/v1/Systems/Node0" {
+		t.Fatalf("unexpected systems: %+v", systems)
+	}
+
+	if err := ResetSystem(ctx, host, "u", "p", true, 2*time.Second, systems[0].Path, "GracefulShutdown"); err != nil {
+		t.Fatalf("ResetSystem failed: %v", err)
+	}
+	// No action target advertised: the conventional path is used
+	if gotPath != "/redfish/v1/Systems/Node0/Actions/ComputerSystem.Reset" || gotType != "GracefulShutdown" {
+		t.Errorf("got POST %q ResetType %q", gotPath, gotType)
+	}
+
+	wctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	sp, err := WaitSystemPower(wctx, host, "u", "p", true, time.Second, 10*time.Millisecond, systems[0].Path, SystemPower.Off)
+	if err != nil {
+		t.Fatalf("WaitSystemPower failed: %v", err)
+	}
+	if !sp.Off() {
+		t.Errorf("PowerState = %q, want Off", sp.PowerState)
+	}
+
+	wctx, cancel = context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := WaitSystemPower(wctx, host, "u", "p", true, time.Second, 10*time.Millisecond, systems[0].Path, SystemPower.Booted); err == nil || !strings.Contains(err.Error(), "PowerState=Off") {
+		t.Fatalf("expected timeout with last state, got %v", err)
+	}
+}
+
+func TestSystemPowerBooted(t *testing.T) {
+	tests := []struct {
+		sp   SystemPower
+		want bool
+	}{
+		{SystemPower{PowerState: "On"}, true},
+		{SystemPower{PowerState: "On", BootProgress: "OSRunning"}, true},
+		{SystemPower{PowerState: "On", BootProgress: "MemoryInitializationStarted"}, false},
+		{SystemPower{PowerState: "PoweringOn"}, false},
+		{SystemPower{PowerState: "Off", BootProgress: "OSRunning"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.sp.Booted(); got != tt.want {
+			t.Errorf("%+v.Booted() = %v, want %v", tt.sp, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTaskFailed is returned by WaitUpdateTask when the BMC reports that the update task
+// ended without applying the image.
+var ErrTaskFailed = errors.New("update task failed")
+
+type rfTaskState struct {
+	TaskState  string `json:"TaskState"`
+	TaskStatus string `json:"TaskStatus"`
+	Messages   []struct {
+		Message string `json:"Message"`
+	} `json:"Messages"`
+}
+
+// WaitUpdateTask polls the task at taskURI every interval until the BMC has finished with the
+// update: the task is Completed, or Pending because the image is staged for a later apply
+// time. A task that ends in Exception, Killed or Cancelled, or completes with a Critical
+// TaskStatus, is reported as ErrTaskFailed. When the BMC reported no task URI, or the task
+// URI is gone (404 or 410, as BMCs purge finished tasks), it waits until GetActiveUpdateTasks
+// finds no running update task instead. It returns the last TaskState seen, and ctx's error
+// when ctx ends first.
+func WaitUpdateTask(ctx context.Context, host, user, pass string, insecure bool, timeout, interval time.Duration, taskURI string) (string, error) {
+	c := newClient(host, user, pass, insecure, timeout)
+	var last string
+	var lastErr error
+	for {
+		if taskURI == "" {
+			active, err := GetActiveUpdateTasks(ctx, host, user, pass, insecure, timeout)
+			switch {
+			case err == nil && len(active) == 0:
+				return "", nil
+			case err == nil:
+				last, lastErr = "running tasks "+strings.Join(active, ", "), nil
+			case ctx.Err() == nil:
+				lastErr = err
+			}
+		} else {
+			var t rfTaskState
+			err := c.get(ctx, taskURI, &t)
+			if err == nil {
+				last, lastErr = t.TaskState, nil
+				switch strings.ToLower(t.TaskState) {
+				case "completed":
+					if strings.EqualFold(t.TaskStatus, "Critical") {
+						return t.TaskState, fmt.Errorf("%w: %s with TaskStatus Critical%s", ErrTaskFailed, t.TaskState, t.messages())
+					}
+					return t.TaskState, nil
+				case "pending":
+					return t.TaskState, nil
+				case "exception", "killed", "cancelled":
+					return t.TaskState, fmt.Errorf("%w: %s%s", ErrTaskFailed, t.TaskState, t.messages())
+				}
+			} else if isGone(err) {
+				taskURI = ""
+				continue
+			} else if ctx.Err() == nil {
+				// A poll cut short by ctx says nothing about the task
+				lastErr = err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return last, fmt.Errorf("%w (last: %v)", ctx.Err(), lastErr)
+			}
+			return last, fmt.Errorf("%w (last: %s)", ctx.Err(), last)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (t rfTaskState) messages() string {
+	var msgs []string
+	for _, m := range t.Messages {
+		if m.Message != "" {
+			msgs = append(msgs, m.Message)
+		}
+	}
+	if len(msgs) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(msgs, "; ")
+}
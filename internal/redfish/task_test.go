@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitUpdateTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		final     string
+		wantState string
+		wantErr   error
+	}{
+		{"completed", `{"TaskState":"Completed","TaskStatus":"OK"}`, "Completed", nil},
+		{"staged", `{"TaskState":"Pending"}`, "Pending", nil},
+		{"exception", `{"TaskState":"Exception","Messages":[{"Message":"image rejected"}]}`, "Exception", ErrTaskFailed},
+		{"critical", `{"TaskState":"Completed","TaskStatus":"Critical"}`, "Completed", ErrTaskFailed},
+		{"never finishes", `{"TaskState":"Running"}`, "Running", context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int32
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/redfish/v1/TaskService/Tasks/1" {
+					http.NotFound(w, r)
+					return
+				}
+				if atomic.AddInt32(&polls, 1) < 3 {
+					_, _ = w.Write([]byte(`{"TaskState":"Running"}`))
+					return
+				}
+				_, _ = w.Write([]byte(tt.final))
+			}))
+			defer ts.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			host := strings.TrimPrefix(ts.URL, "https://")
+			state, err := WaitUpdateTask(ctx, host, "u", "p", true, time.Second, 10*time.Millisecond, "/redfish/v1/TaskService/Tasks/1")
+			if state != tt.wantState || !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("got (%q, %v), want (%q, %v)", state, err, tt.wantState, tt.wantErr)
+			}
+			if atomic.LoadInt32(&polls) < 3 {
+				t.Errorf("returned after %d polls, before the task left Running", polls)
+			}
+		})
+	}
+}
+
+func TestWaitUpdateTaskWithoutURI(t *testing.T) {
+	var polls int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/TaskService/Tasks":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/TaskService/Tasks/7"}]}`))
+		case "/redfish/v1/TaskService/Tasks/7":
+			if atomic.AddInt32(&polls, 1) < 3 {
+				_, _ = w.Write([]byte(`{"Id":"7","Name":"Firmware Update","TaskState":"Running"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Id":"7","Name":"Firmware Update","TaskState":"Completed"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	if _, err := WaitUpdateTask(context.Background(), host, "u", "p", true, time.Second, 10*time.Millisecond, ""); err != nil {
+		t.Fatalf("WaitUpdateTask failed: %v", err)
+	}
+	if atomic.LoadInt32(&polls) != 3 {
+		t.Errorf("got %d task polls, want 3", polls)
+	}
+}
+
+func TestWaitUpdateTaskPurged(t *testing.T) {
+	var polls, listed int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/TaskService/Tasks/1":
+			// The BMC purges the task once it has finished
+			if atomic.AddInt32(&polls, 1) < 3 {
+				_, _ = w.Write([]byte(`{"TaskState":"Running"}`))
+				return
+			}
+			http.NotFound(w, r)
+		case "/redfish/v1/TaskService/Tasks":
+			atomic.AddInt32(&listed, 1)
+			_, _ = w.Write([]byte(`{"Members":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	host := strings.TrimPrefix(ts.URL, "https://")
+	if _, err := WaitUpdateTask(ctx, host, "u", "p", true, time.Second, 10*time.Millisecond, "/redfish/v1/TaskService/Tasks/1"); err != nil {
+		t.Fatalf("WaitUpdateTask failed: %v", err)
+	}
+	if atomic.LoadInt32(&polls) != 3 || atomic.LoadInt32(&listed) != 1 {
+		t.Errorf("got %d task polls and %d task list reads, want 3 and 1", polls, listed)
+	}
+}
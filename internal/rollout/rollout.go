@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package rollout plans staged rollouts: an optional canary group followed by waves
+// grouped by cabinet or chassis xname prefix, with a failure threshold to halt later waves.
+package rollout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bootstrap/internal/xname"
+)
+
+// GroupBy selects how hosts after the canary group are split into waves.
+type GroupBy string
+
+// Supported wave groupings.
+const (
+	GroupNone    GroupBy = "none"
+	GroupCabinet GroupBy = "cabinet"
+	GroupChassis GroupBy = "chassis"
+)
+
+// ParseGroupBy validates a wave grouping name. An empty string means GroupNone.
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch g := GroupBy(strings.ToLower(strings.TrimSpace(s))); g {
+	case "", GroupNone:
+		return GroupNone, nil
+	case GroupCabinet, GroupChassis:
+		return g, nil
+	default:
+		return "", fmt.Errorf("unknown wave grouping %q (use none|cabinet|chassis)", s)
+	}
+}
+
+// Wave is a named set of hosts, given as indexes into the slice passed to Plan.
+type Wave struct {
+	Name    string
+	Members []int
+}
+
+// Plan splits hosts (identified by xname) into waves. The first canary hosts form a
+// "canary" wave; the rest are grouped by cabinet or chassis in order of first appearance.
+// Hosts whose xname has no cabinet/chassis prefix are collected in an "ungrouped" wave.
+func Plan(xnames []string, canary int, groupBy GroupBy) []Wave {
+	var waves []Wave
+	start := 0
+	if canary > 0 && len(xnames) > 0 {
+		start = min(canary, len(xnames))
+		w := Wave{Name: "canary"}
+		for i := 0; i < start; i++ {
+			w.Members = append(w.Members, i)
+		}
+		waves = append(waves, w)
+	}
+	if start >= len(xnames) {
+		return waves
+	}
+
+	if groupBy == GroupNone || groupBy == "" {
+		w := Wave{Name: "all"}
+		if len(waves) > 0 {
+			w.Name = "remaining"
+		}
+		for i := start; i < len(xnames); i++ {
+			w.Members = append(w.Members, i)
+		}
+		return append(waves, w)
+	}
+
+	index := map[string]int{}
+	var ungrouped []int
+	for i := start; i < len(xnames); i++ {
+		key := xname.Chassis(xnames[i])
+		if groupBy == GroupCabinet {
+			key = xname.Cabinet(xnames[i])
+		}
+		if key == "" {
+			ungrouped = append(ungrouped, i)
+			continue
+		}
+		pos, ok := index[key]
+		if !ok {
+			pos = len(waves)
+			index[key] = pos
+			waves = append(waves, Wave{Name: key})
+		}
+		waves[pos].Members = append(waves[pos].Members, i)
+	}
+	if len(ungrouped) > 0 {
+		waves = append(waves, Wave{Name: "ungrouped", Members: ungrouped})
+	}
+	return waves
+}
+
+// Threshold is the number or percentage of failed hosts tolerated before a rollout halts.
+type Threshold struct {
+	Count   int
+	Percent float64
+	// IsPercent selects Percent instead of Count.
+	IsPercent bool
+}
+
+// ParseThreshold parses a failure threshold such as "3" (hosts) or "10%" (of hosts attempted).
+func ParseThreshold(s string) (Threshold, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		p, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || p < 0 || p > 100 {
+			return Threshold{}, fmt.Errorf("invalid failure threshold %q: percentage must be between 0%% and 100%%", s)
+		}
+		return Threshold{Percent: p, IsPercent: true}, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return Threshold{}, fmt.Errorf("invalid failure threshold %q: use a host count or a percentage like 10%%", s)
+	}
+	return Threshold{Count: n}, nil
+}
+
+// Exceeded reports whether failed out of attempted hosts goes beyond the threshold.
+func (t Threshold) Exceeded(failed, attempted int) bool {
+	if failed == 0 {
+		return false
+	}
+	if t.IsPercent {
+		return attempted > 0 && float64(failed)*100/float64(attempted) > t.Percent
+	}
+	return failed > t.Count
+}
+
+// String formats the threshold as accepted by ParseThreshold.
+func (t Threshold) String() string {
+	if t.IsPercent {
+		return strconv.FormatFloat(t.Percent, 'f', -1, 64) + "%"
+	}
+	return strconv.Itoa(t.Count)
+}
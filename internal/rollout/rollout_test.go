@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package rollout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	xnames := []string{
+		"x9000c1s0b0",
+		"x9000c1s0b1",
+		"x9000c3s0b0",
+		"x9001c1s0b0",
+		"x9000c1s1b0",
+		"10.0.0.9",
+	}
+	tests := []struct {
+		name    string
+		canary  int
+		groupBy GroupBy
+		want    []Wave
+	}{
+		{
+			name:    "single wave",
+			groupBy: GroupNone,
+			want:    []Wave{{Name: "all", Members: []int{0, 1, 2, 3, 4, 5}}},
+		},
+		{
+			name:    "canary then remaining",
+			canary:  2,
+			groupBy: GroupNone,
+			want: []Wave{
+				{Name: "canary", Members: []int{0, 1}},
+				{Name: "remaining", Members: []int{2, 3, 4, 5}},
+			},
+		},
+		{
+			name:    "canary then chassis",
+			canary:  1,
+			groupBy: GroupChassis,
+			want: []Wave{
+				{Name: "canary", Members: []int{0}},
+				{Name: "x9000c1", Members: []int{1, 4}},
+				{Name: "x9000c3", Members: []int{2}},
+				{Name: "x9001c1", Members: []int{3}},
+				{Name: "ungrouped", Members: []int{5}},
+			},
+		},
+		{
+			name:    "cabinet",
+			groupBy: GroupCabinet,
+			want: []Wave{
+				{Name: "x9000", Members: []int{0, 1, 2, 4}},
+				{Name: "x9001", Members: []int{3}},
+				{Name: "ungrouped", Members: []int{5}},
+			},
+		},
+		{
+			name:    "canary covers everything",
+			canary:  10,
+			groupBy: GroupChassis,
+			want:    []Wave{{Name: "canary", Members: []int{0, 1, 2, 3, 4, 5}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Plan(xnames, tt.canary, tt.groupBy)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Plan mismatch:\n got: %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	for in, want := range map[string]GroupBy{"": GroupNone, "Chassis": GroupChassis, "cabinet": GroupCabinet} {
+		got, err := ParseGroupBy(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseGroupBy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseGroupBy("slot"); err == nil {
+		t.Fatal("expected error for unknown grouping")
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	tests := []struct {
+		spec      string
+		failed    int
+		attempted int
+		want      bool
+	}{
+		{"0", 0, 10, false},
+		{"0", 1, 10, true},
+		{"2", 2, 10, false},
+		{"2", 3, 10, true},
+		{"10%", 1, 10, false},
+		{"10%", 2, 10, true},
+		{"0%", 1, 100, true},
+	}
+	for _, tt := range tests {
+		th, err := ParseThreshold(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseThreshold(%q): %v", tt.spec, err)
+		}
+		if got := th.Exceeded(tt.failed, tt.attempted); got != tt.want {
+			t.Errorf("%s: Exceeded(%d, %d) = %v, want %v", tt.spec, tt.failed, tt.attempted, got, tt.want)
+		}
+		if th.String() != tt.spec {
+			t.Errorf("String() = %q, want %q", th.String(), tt.spec)
+		}
+	}
+	for _, bad := range []string{"", "-1", "150%", "many"} {
+		if _, err := ParseThreshold(bad); err == nil {
+			t.Errorf("ParseThreshold(%q): expected error", bad)
+		}
+	}
+}
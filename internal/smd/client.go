@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package smd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"bootstrap/internal/diag"
+)
+
+// API paths relative to the SMD base URL.
+const (
+	RedfishEndpointsPath   = "/hsm/v2/Inventory/RedfishEndpoints"
+	ComponentsPath         = "/hsm/v2/State/Components"
+	ComponentEndpointsPath = "/hsm/v2/Inventory/ComponentEndpoints"
+	EthernetInterfacesPath = "/hsm/v2/Inventory/EthernetInterfaces"
+)
+
+// Client calls the SMD API at a base URL such as https://smd.example:27779, authenticating
+// with a bearer token when one is set.
+type Client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// NewClient returns a client for the SMD instance at baseURL.
+func NewClient(baseURL, token string, insecure bool, timeout time.Duration) *Client {
+	tr := &http.Transport{}
+	if insecure {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &Client{
+		base:  strings.TrimRight(baseURL, "/"),
+		token: token,
+		http:  &http.Client{Timeout: timeout, Transport: tr},
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out
+// when out is not nil.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	url := c.base + path
+	diag.Logf("%s %s", method, url)
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	diag.Logf("%s %s -> %s", method, url, resp.Status)
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("smd %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(b)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Post creates the objects of p in SMD: RedfishEndpoints and Components in one request each,
+// and EthernetInterfaces one by one, as the SMD API takes them. ComponentEndpoints are not
+// posted: SMD has no POST for them and creates them itself when it discovers the
+// RedfishEndpoints. It attempts every request and returns all failures joined.
+func (c *Client) Post(ctx context.Context, p Payload) error {
+	var errs []error
+	if len(p.RedfishEndpoints) > 0 {
+		errs = append(errs, c.do(ctx, http.MethodPost, RedfishEndpointsPath, map[string]any{"RedfishEndpoints": p.RedfishEndpoints}, nil))
+	}
+	if len(p.Components) > 0 {
+		errs = append(errs, c.do(ctx, http.MethodPost, ComponentsPath, map[string]any{"Components": p.Components}, nil))
+	}
+	for _, ei := range p.EthernetInterfaces {
+		errs = append(errs, c.do(ctx, http.MethodPost, EthernetInterfacesPath, ei, nil))
+	}
+	return errors.Join(errs...)
+}
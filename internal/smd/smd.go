@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package smd converts inventories to OpenCHAMI State Management Database (SMD) objects
+// and talks to the SMD HSM v2 API.
+package smd
+
+import (
+	"net"
+	"strings"
+
+	"bootstrap/internal/inventory"
+	"bootstrap/internal/xname"
+)
+
+// RedfishEndpoint is an SMD RedfishEndpoint: a BMC that SMD discovers through Redfish.
+type RedfishEndpoint struct {
+	ID                 string `json:"ID"`
+	Type               string `json:"Type"`
+	Hostname           string `json:"Hostname,omitempty"`
+	FQDN               string `json:"FQDN"`
+	IPAddress          string `json:"IPAddress,omitempty"`
+	MACAddr            string `json:"MACAddr,omitempty"`
+	User               string `json:"User,omitempty"`
+	Password           string `json:"Password,omitempty"`
+	Enabled            bool   `json:"Enabled"`
+	RediscoverOnUpdate bool   `json:"RediscoverOnUpdate"`
+}
+
+// Component is an SMD State Component.
+type Component struct {
+	ID      string `json:"ID"`
+	Type    string `json:"Type"`
+	State   string `json:"State"`
+	Flag    string `json:"Flag"`
+	Enabled bool   `json:"Enabled"`
+	Role    string `json:"Role,omitempty"`
+	SubRole string `json:"SubRole,omitempty"`
+	NID     int    `json:"NID,omitempty"`
+}
+
+// ComponentEndpoint links a component to the Redfish endpoint that manages it.
+type ComponentEndpoint struct {
+	ID                  string `json:"ID"`
+	Type                string `json:"Type"`
+	RedfishType         string `json:"RedfishType"`
+	RedfishSubtype      string `json:"RedfishSubtype"`
+	MACAddr             string `json:"MACAddr,omitempty"`
+	RedfishEndpointID   string `json:"RedfishEndpointID"`
+	RedfishEndpointFQDN string `json:"RedfishEndpointFQDN,omitempty"`
+}
+
+// IPAddress is one address of an EthernetInterface.
+type IPAddress struct {
+	IPAddress string `json:"IPAddress"`
+	Network   string `json:"Network,omitempty"`
+}
+
+// EthernetInterface is an SMD EthernetInterface: a MAC address, its IPs and the component
+// that owns it.
+type EthernetInterface struct {
+	ID          string      `json:"ID"`
+	Description string      `json:"Description,omitempty"`
+	MACAddress  string      `json:"MACAddress"`
+	IPAddresses []IPAddress `json:"IPAddresses"`
+	ComponentID string      `json:"ComponentID"`
+}
+
+// Payload holds every SMD object generated from an inventory.
+type Payload struct {
+	RedfishEndpoints   []RedfishEndpoint   `json:"RedfishEndpoints"`
+	Components         []Component         `json:"Components"`
+	ComponentEndpoints []ComponentEndpoint `json:"ComponentEndpoints"`
+	EthernetInterfaces []EthernetInterface `json:"EthernetInterfaces"`
+}
+
+// Credentials are stored on RedfishEndpoints so SMD can discover them. Empty fields are
+// left out of the payload.
+type Credentials struct {
+	User     string
+	Password string
+}
+
+// FromInventory returns the SMD objects for doc: a RedfishEndpoint per BMC, a Component and
+// ComponentEndpoint per node, and an EthernetInterface per known MAC address of either.
+func FromInventory(doc *inventory.FileFormat, creds Credentials) Payload {
+	p := Payload{
+		RedfishEndpoints:   []RedfishEndpoint{},
+		Components:         []Component{},
+		ComponentEndpoints: []ComponentEndpoint{},
+		EthernetInterfaces: []EthernetInterface{},
+	}
+	fqdn := map[string]string{}
+	for _, b := range doc.BMCs {
+		ep := RedfishEndpoint{
+			ID:                 b.Xname,
+			Type:               "NodeBMC",
+			Hostname:           b.Hostname,
+			FQDN:               address(b),
+			IPAddress:          hostIP(b.IP),
+			MACAddr:            b.MAC,
+			User:               creds.User,
+			Password:           creds.Password,
+			Enabled:            true,
+			RediscoverOnUpdate: true,
+		}
+		fqdn[b.Xname] = ep.FQDN
+		p.RedfishEndpoints = append(p.RedfishEndpoints, ep)
+		p.EthernetInterfaces = append(p.EthernetInterfaces, ethernetInterfaces(b)...)
+	}
+	for _, n := range doc.Nodes {
+		p.Components = append(p.Components, Component{
+			ID:      n.Xname,
+			Type:    "Node",
+			State:   "Populated",
+			Flag:    "OK",
+			Enabled: true,
+			Role:    n.Role,
+			SubRole: n.SubRole,
+			NID:     n.NID,
+		})
+		bmc := n.BMC
+		if bmc == "" {
+			bmc = xname.NodeToBMC(n.Xname)
+		}
+		if bmc != "" {
+			p.ComponentEndpoints = append(p.ComponentEndpoints, ComponentEndpoint{
+				ID:                  n.Xname,
+				Type:                "Node",
+				RedfishType:         "ComputerSystem",
+				RedfishSubtype:      "Physical",
+				MACAddr:             n.MAC,
+				RedfishEndpointID:   bmc,
+				RedfishEndpointFQDN: fqdn[bmc],
+			})
+		}
+		p.EthernetInterfaces = append(p.EthernetInterfaces, ethernetInterfaces(n)...)
+	}
+	return p
+}
+
+// address is how SMD reaches a BMC: its IP, or its xname when there is none.
+func address(e inventory.Entry) string {
+	if e.IP != "" {
+		return e.IP
+	}
+	return e.Xname
+}
+
+// hostIP returns the address part of an inventory ip, which may be a host:port endpoint
+// reached through a proxy or port forward.
+func hostIP(ip string) string {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
+
+// ethernetInterfaces returns one EthernetInterface per MAC address of e: the primary MAC and
+// IP plus interfaces[], merged by MAC and ordered by first appearance. A host:port primary
+// IP is an endpoint rather than the address of the MAC, so it is left out.
+func ethernetInterfaces(e inventory.Entry) []EthernetInterface {
+	var out []EthernetInterface
+	byMAC := map[string]int{}
+	add := func(name, mac, ip, network string) {
+		if mac == "" {
+			return
+		}
+		id := InterfaceID(mac)
+		i, ok := byMAC[id]
+		if !ok {
+			i = len(out)
+			byMAC[id] = i
+			out = append(out, EthernetInterface{ID: id, MACAddress: strings.ToLower(mac), IPAddresses: []IPAddress{}, ComponentID: e.Xname})
+		}
+		if out[i].Description == "" {
+			out[i].Description = name
+		}
+		if ip == "" {
+			return
+		}
+		for j, a := range out[i].IPAddresses {
+			if a.IPAddress == ip {
+				if a.Network == "" {
+					out[i].IPAddresses[j].Network = network
+				}
+				return
+			}
+		}
+		out[i].IPAddresses = append(out[i].IPAddresses, IPAddress{IPAddress: ip, Network: network})
+	}
+	ip := e.IP
+	if hostIP(ip) != ip {
+		ip = ""
+	}
+	add("", e.MAC, ip, "")
+	for _, iface := range e.Interfaces {
+		add(iface.Name, iface.MAC, iface.IP, iface.Network)
+	}
+	return out
+}
+
+// InterfaceID returns the SMD EthernetInterface ID for a MAC address: its lower-case hex
+// digits without separators.
+func InterfaceID(mac string) string {
+	if hw, err := net.ParseMAC(mac); err == nil {
+		mac = hw.String()
+	}
+	return strings.NewReplacer(":", "", "-", "", ".", "").Replace(strings.ToLower(mac))
+}
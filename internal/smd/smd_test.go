@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package smd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/internal/inventory"
+)
+
+func testInventory() *inventory.FileFormat {
+	return &inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"}},
+		Nodes: []inventory.Entry{{
+			Xname: "x9000c1s0b0n0", MAC: "AA:BB:CC:DD:EE:01", IP: "10.42.0.1", NID: 1, Role: "Compute",
+			Interfaces: []inventory.Interface{
+				{Name: "eth0", MAC: "aa:bb:cc:dd:ee:01", IP: "10.42.0.1", Network: "nmn"},
+				{Name: "hsn0", MAC: "02:00:00:00:00:01", IP: "10.150.0.1", Network: "hsn"},
+			},
+		}},
+	}
+}
+
+func TestFromInventory(t *testing.T) {
+	p := FromInventory(testInventory(), Credentials{User: "root"})
+
+	if got := p.RedfishEndpoints; len(got) != 1 || got[0].ID != "x9000c1s0b0" || got[0].FQDN != "192.168.100.1" || got[0].User != "root" || got[0].Password != "" || !got[0].Enabled {
+		t.Errorf("RedfishEndpoints = %+v", got)
+	}
+	if got := p.Components; len(got) != 1 || got[0].ID != "x9000c1s0b0n0" || got[0].NID != 1 || got[0].Role != "Compute" || got[0].Type != "Node" {
+		t.Errorf("Components = %+v", got)
+	}
+	if got := p.ComponentEndpoints; len(got) != 1 || got[0].RedfishEndpointID != "x9000c1s0b0" || got[0].RedfishEndpointFQDN != "192.168.100.1" {
+		t.Errorf("ComponentEndpoints = %+v", got)
+	}
+	want := []EthernetInterface{
+		{ID: "022328013000", MACAddress: "02:23:28:01:30:00", IPAddresses: []IPAddress{{IPAddress: "192.168.100.1"}}, ComponentID: "x9000c1s0b0"},
+		{ID: "aabbccddee01", Description: "eth0", MACAddress: "aa:bb:cc:dd:ee:01", IPAddresses: []IPAddress{{IPAddress: "10.42.0.1", Network: "nmn"}}, ComponentID: "x9000c1s0b0n0"},
+		{ID: "020000000001", Description: "hsn0", MACAddress: "02:00:00:00:00:01", IPAddresses: []IPAddress{{IPAddress: "10.150.0.1", Network: "hsn"}}, ComponentID: "x9000c1s0b0n0"},
+	}
+	if !reflect.DeepEqual(p.EthernetInterfaces, want) {
+		t.Errorf("EthernetInterfaces = %+v\nwant %+v", p.EthernetInterfaces, want)
+	}
+
+	// A BMC reached through a port forward keeps the port in FQDN only
+	doc := testInventory()
+	doc.BMCs[0].IP = "127.0.0.1:8443"
+	p = FromInventory(doc, Credentials{})
+	if ep := p.RedfishEndpoints[0]; ep.FQDN != "127.0.0.1:8443" || ep.IPAddress != "127.0.0.1" {
+		t.Errorf("endpoint BMC: FQDN %q, IPAddress %q", ep.FQDN, ep.IPAddress)
+	}
+	if ips := p.EthernetInterfaces[0].IPAddresses; len(ips) != 0 {
+		t.Errorf("endpoint BMC interface IPs = %+v, want none", ips)
+	}
+}
+
+func TestClientPost(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string][]json.RawMessage{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[r.URL.Path] = append(bodies[r.URL.Path], b)
+		mu.Unlock()
+		switch r.URL.Path {
+		case ComponentsPath:
+			http.Error(w, "exists", http.StatusConflict)
+		case RedfishEndpointsPath, EthernetInterfacesPath:
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "secret", false, 5*time.Second)
+	err := c.Post(context.Background(), FromInventory(testInventory(), Credentials{}))
+	if err == nil || err.Error() != "smd POST "+ComponentsPath+": 409 Conflict: exists" {
+		t.Fatalf("expected the Components failure only, got %v", err)
+	}
+	if len(bodies) != 3 || len(bodies[RedfishEndpointsPath]) != 1 || len(bodies[ComponentsPath]) != 1 || len(bodies[EthernetInterfacesPath]) != 3 {
+		t.Fatalf("unexpected requests: %v", bodies)
+	}
+	var eps struct{ RedfishEndpoints []RedfishEndpoint }
+	if err := json.Unmarshal(bodies[RedfishEndpointsPath][0], &eps); err != nil || eps.RedfishEndpoints[0].ID != "x9000c1s0b0" {
+		t.Fatalf("RedfishEndpoints body: %s (%v)", bodies[RedfishEndpointsPath][0], err)
+	}
+}
@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package smd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"bootstrap/internal/inventory"
+	"bootstrap/internal/xname"
+)
+
+// Action is what Apply does with one object.
+type Action string
+
+// Plan actions.
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Object kinds in a plan.
+const (
+	KindRedfishEndpoint   = "RedfishEndpoint"
+	KindEthernetInterface = "EthernetInterface"
+)
+
+// Change is one step of a sync plan. Endpoint or Interface holds the local object for
+// creates and updates; Fields names what differs for updates.
+type Change struct {
+	Action    Action
+	Kind      string
+	ID        string
+	Fields    []string
+	Endpoint  *RedfishEndpoint
+	Interface *EthernetInterface
+}
+
+// Get reads the RedfishEndpoints, Components, ComponentEndpoints and EthernetInterfaces
+// SMD holds.
+func (c *Client) Get(ctx context.Context) (Payload, error) {
+	var p Payload
+	var eps struct{ RedfishEndpoints []RedfishEndpoint }
+	if err := c.do(ctx, http.MethodGet, RedfishEndpointsPath, nil, &eps); err != nil {
+		return p, err
+	}
+	var comps struct{ Components []Component }
+	if err := c.do(ctx, http.MethodGet, ComponentsPath, nil, &comps); err != nil {
+		return p, err
+	}
+	var ceps struct{ ComponentEndpoints []ComponentEndpoint }
+	if err := c.do(ctx, http.MethodGet, ComponentEndpointsPath, nil, &ceps); err != nil {
+		return p, err
+	}
+	// EthernetInterfaces are returned as a bare array
+	if err := c.do(ctx, http.MethodGet, EthernetInterfacesPath, nil, &p.EthernetInterfaces); err != nil {
+		return p, err
+	}
+	p.RedfishEndpoints, p.Components, p.ComponentEndpoints = eps.RedfishEndpoints, comps.Components, ceps.ComponentEndpoints
+	return p, nil
+}
+
+// Diff compares the RedfishEndpoints and EthernetInterfaces of local against those in
+// SMD (remote) and returns the changes that make SMD match local, ordered by kind, action
+// and ID. Credentials are not compared because SMD does not return them.
+func Diff(local, remote Payload) []Change {
+	var changes []Change
+
+	remoteEPs := map[string]RedfishEndpoint{}
+	for _, ep := range remote.RedfishEndpoints {
+		remoteEPs[ep.ID] = ep
+	}
+	for i := range local.RedfishEndpoints {
+		ep := &local.RedfishEndpoints[i]
+		old, ok := remoteEPs[ep.ID]
+		delete(remoteEPs, ep.ID)
+		if !ok {
+			changes = append(changes, Change{Action: Create, Kind: KindRedfishEndpoint, ID: ep.ID, Endpoint: ep})
+			continue
+		}
+		var fields []string
+		if ep.Hostname != "" && ep.Hostname != old.Hostname {
+			fields = append(fields, "Hostname")
+		}
+		if ep.FQDN != old.FQDN {
+			fields = append(fields, "FQDN")
+		}
+		if ep.IPAddress != old.IPAddress {
+			fields = append(fields, "IPAddress")
+		}
+		if InterfaceID(ep.MACAddr) != InterfaceID(old.MACAddr) {
+			fields = append(fields, "MACAddr")
+		}
+		if ep.Enabled != old.Enabled {
+			fields = append(fields, "Enabled")
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{Action: Update, Kind: KindRedfishEndpoint, ID: ep.ID, Fields: fields, Endpoint: ep})
+		}
+	}
+	for id := range remoteEPs {
+		changes = append(changes, Change{Action: Delete, Kind: KindRedfishEndpoint, ID: id})
+	}
+
+	remoteEIs := map[string]EthernetInterface{}
+	for _, ei := range remote.EthernetInterfaces {
+		remoteEIs[ei.ID] = ei
+	}
+	for i := range local.EthernetInterfaces {
+		ei := &local.EthernetInterfaces[i]
+		old, ok := remoteEIs[ei.ID]
+		delete(remoteEIs, ei.ID)
+		if !ok {
+			changes = append(changes, Change{Action: Create, Kind: KindEthernetInterface, ID: ei.ID, Interface: ei})
+			continue
+		}
+		var fields []string
+		if ei.Description != "" && ei.Description != old.Description {
+			fields = append(fields, "Description")
+		}
+		if ei.ComponentID != old.ComponentID {
+			fields = append(fields, "ComponentID")
+		}
+		if !sameIPs(ei.IPAddresses, old.IPAddresses) {
+			fields = append(fields, "IPAddresses")
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{Action: Update, Kind: KindEthernetInterface, ID: ei.ID, Fields: fields, Interface: ei})
+		}
+	}
+	for id := range remoteEIs {
+		changes = append(changes, Change{Action: Delete, Kind: KindEthernetInterface, ID: id})
+	}
+
+	order := map[Action]int{Create: 0, Update: 1, Delete: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Kind != b.Kind {
+			return a.Kind == KindRedfishEndpoint
+		}
+		if a.Action != b.Action {
+			return order[a.Action] < order[b.Action]
+		}
+		return a.ID < b.ID
+	})
+	return changes
+}
+
+// sameIPs reports whether two address lists hold the same IP/network pairs in any order.
+func sameIPs(a, b []IPAddress) bool {
+	key := func(list []IPAddress) []string {
+		out := make([]string, 0, len(list))
+		for _, ip := range list {
+			out = append(out, ip.IPAddress+"/"+ip.Network)
+		}
+		sort.Strings(out)
+		return out
+	}
+	return reflect.DeepEqual(key(a), key(b))
+}
+
+// Apply makes the calls for changes: creates are POSTed, RedfishEndpoint updates PATCH the
+// changed fields (leaving stored credentials alone), EthernetInterface updates PATCH
+// Description, ComponentID and IPAddresses, and deletes DELETE the object. It attempts every
+// change and returns all failures joined.
+func (c *Client) Apply(ctx context.Context, changes []Change) error {
+	var errs []error
+	var create []RedfishEndpoint
+	for _, ch := range changes {
+		if ch.Kind == KindRedfishEndpoint && ch.Action == Create {
+			create = append(create, *ch.Endpoint)
+		}
+	}
+	if len(create) > 0 {
+		errs = append(errs, c.do(ctx, http.MethodPost, RedfishEndpointsPath, map[string]any{"RedfishEndpoints": create}, nil))
+	}
+	for _, ch := range changes {
+		var path string
+		switch ch.Kind {
+		case KindRedfishEndpoint:
+			path = RedfishEndpointsPath + "/" + url.PathEscape(ch.ID)
+		case KindEthernetInterface:
+			path = EthernetInterfacesPath + "/" + url.PathEscape(ch.ID)
+		default:
+			errs = append(errs, fmt.Errorf("unknown kind %s", ch.Kind))
+			continue
+		}
+		var err error
+		switch {
+		case ch.Action == Delete:
+			err = c.do(ctx, http.MethodDelete, path, nil, nil)
+		case ch.Kind == KindEthernetInterface && ch.Action == Create:
+			err = c.do(ctx, http.MethodPost, EthernetInterfacesPath, ch.Interface, nil)
+		case ch.Kind == KindEthernetInterface && ch.Action == Update:
+			err = c.do(ctx, http.MethodPatch, path, map[string]any{
+				"Description": ch.Interface.Description,
+				"ComponentID": ch.Interface.ComponentID,
+				"IPAddresses": ch.Interface.IPAddresses,
+			}, nil)
+		case ch.Kind == KindRedfishEndpoint && ch.Action == Update:
+			patch := map[string]any{}
+			v := reflect.ValueOf(*ch.Endpoint)
+			for _, f := range ch.Fields {
+				patch[f] = v.FieldByName(f).Interface()
+			}
+			err = c.do(ctx, http.MethodPatch, path, patch, nil)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s %s: %w", ch.Action, ch.Kind, ch.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ToInventory rebuilds an inventory from SMD objects: a BMC per RedfishEndpoint and a node
+// per Node component, with the node's NID, role, parent BMC (from its ComponentEndpoint or
+// xname) and interfaces from the EthernetInterfaces that belong to it. The primary MAC and
+// IP are those of the interface the ComponentEndpoint names, or of the first interface.
+func ToInventory(p Payload) *inventory.FileFormat {
+	doc := &inventory.FileFormat{APIVersion: inventory.APIVersion, Kind: inventory.Kind, BMCs: []inventory.Entry{}, Nodes: []inventory.Entry{}}
+
+	ifaces := map[string][]EthernetInterface{}
+	for _, ei := range p.EthernetInterfaces {
+		ifaces[ei.ComponentID] = append(ifaces[ei.ComponentID], ei)
+	}
+	for _, list := range ifaces {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	ceps := map[string]ComponentEndpoint{}
+	for _, cep := range p.ComponentEndpoints {
+		ceps[cep.ID] = cep
+	}
+
+	for _, ep := range p.RedfishEndpoints {
+		b := inventory.Entry{Xname: ep.ID, MAC: strings.ToLower(ep.MACAddr), IP: ep.IPAddress, Hostname: ep.Hostname}
+		if b.IP == "" && ep.FQDN != ep.ID || b.IP != "" && hostIP(ep.FQDN) == b.IP {
+			// No IP, or a host:port endpoint whose port is only in FQDN
+			b.IP = ep.FQDN
+		}
+		if b.MAC == "" && len(ifaces[ep.ID]) > 0 {
+			b.MAC = ifaces[ep.ID][0].MACAddress
+		}
+		doc.BMCs = append(doc.BMCs, b)
+	}
+	for _, c := range p.Components {
+		if c.Type != "Node" {
+			continue
+		}
+		n := inventory.Entry{Xname: c.ID, NID: c.NID, Role: c.Role, SubRole: c.SubRole, BMC: xname.NodeToBMC(c.ID)}
+		cep, hasCEP := ceps[c.ID]
+		if hasCEP && cep.RedfishEndpointID != "" {
+			n.BMC = cep.RedfishEndpointID
+		}
+		for i, ei := range ifaces[c.ID] {
+			iface := inventory.Interface{Name: ei.Description, MAC: ei.MACAddress}
+			if iface.Name == "" {
+				iface.Name = fmt.Sprintf("nic%d", i)
+			}
+			if len(ei.IPAddresses) > 0 {
+				iface.IP, iface.Network = ei.IPAddresses[0].IPAddress, ei.IPAddresses[0].Network
+			}
+			n.Interfaces = append(n.Interfaces, iface)
+			if n.MAC == "" || (hasCEP && InterfaceID(cep.MACAddr) == ei.ID) {
+				n.MAC, n.IP = iface.MAC, iface.IP
+			}
+		}
+		doc.Nodes = append(doc.Nodes, n)
+	}
+	sort.Slice(doc.BMCs, func(i, j int) bool { return doc.BMCs[i].Xname < doc.BMCs[j].Xname })
+	sort.Slice(doc.Nodes, func(i, j int) bool { return doc.Nodes[i].Xname < doc.Nodes[j].Xname })
+	return doc
+}
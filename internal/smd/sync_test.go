@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package smd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/internal/inventory"
+)
+
+// fakeSMD keeps RedfishEndpoints and EthernetInterfaces in memory and records every
+// modifying request.
+type fakeSMD struct {
+	mu       sync.Mutex
+	state    Payload
+	requests []string
+}
+
+func (f *fakeSMD) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Method != http.MethodGet {
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	}
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == RedfishEndpointsPath:
+		json.NewEncoder(w).Encode(map[string]any{"RedfishEndpoints": f.state.RedfishEndpoints}) //nolint:errcheck
+	case r.Method == http.MethodGet && r.URL.Path == ComponentsPath:
+		json.NewEncoder(w).Encode(map[string]any{"Components": f.state.Components}) //nolint:errcheck
+	case r.Method == http.MethodGet && r.URL.Path == ComponentEndpointsPath:
+		json.NewEncoder(w).Encode(map[string]any{"ComponentEndpoints": f.state.ComponentEndpoints}) //nolint:errcheck
+	case r.Method == http.MethodGet && r.URL.Path == EthernetInterfacesPath:
+		json.NewEncoder(w).Encode(f.state.EthernetInterfaces) //nolint:errcheck
+	case r.Method == http.MethodPost && r.URL.Path == RedfishEndpointsPath:
+		var body struct{ RedfishEndpoints []RedfishEndpoint }
+		json.NewDecoder(r.Body).Decode(&body) //nolint:errcheck
+		f.state.RedfishEndpoints = append(f.state.RedfishEndpoints, body.RedfishEndpoints...)
+	case r.Method == http.MethodPost && r.URL.Path == EthernetInterfacesPath:
+		var ei EthernetInterface
+		json.NewDecoder(r.Body).Decode(&ei) //nolint:errcheck
+		f.state.EthernetInterfaces = append(f.state.EthernetInterfaces, ei)
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, RedfishEndpointsPath+"/"):
+		for i := range f.state.RedfishEndpoints {
+			if f.state.RedfishEndpoints[i].ID == id {
+				json.NewDecoder(r.Body).Decode(&f.state.RedfishEndpoints[i]) //nolint:errcheck
+			}
+		}
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, EthernetInterfacesPath+"/"):
+		for i := range f.state.EthernetInterfaces {
+			if f.state.EthernetInterfaces[i].ID == id {
+				json.NewDecoder(r.Body).Decode(&f.state.EthernetInterfaces[i]) //nolint:errcheck
+			}
+		}
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, RedfishEndpointsPath+"/"):
+		eps := f.state.RedfishEndpoints[:0]
+		for _, ep := range f.state.RedfishEndpoints {
+			if ep.ID != id {
+				eps = append(eps, ep)
+			}
+		}
+		f.state.RedfishEndpoints = eps
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, EthernetInterfacesPath+"/"):
+		eis := f.state.EthernetInterfaces[:0]
+		for _, ei := range f.state.EthernetInterfaces {
+			if ei.ID != id {
+				eis = append(eis, ei)
+			}
+		}
+		f.state.EthernetInterfaces = eis
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSyncDiffAndApply(t *testing.T) {
+	local := FromInventory(testInventory(), Credentials{})
+	fake := &fakeSMD{state: Payload{
+		RedfishEndpoints: []RedfishEndpoint{
+			{ID: "x9000c1s0b0", Type: "NodeBMC", FQDN: "192.168.100.9", IPAddress: "192.168.100.9", MACAddr: "02:23:28:01:30:00", Enabled: true},
+			{ID: "x9000c9s0b0", Type: "NodeBMC", FQDN: "192.168.100.50", Enabled: true},
+		},
+		EthernetInterfaces: []EthernetInterface{
+			{ID: "022328013000", MACAddress: "02:23:28:01:30:00", IPAddresses: []IPAddress{{IPAddress: "192.168.100.1"}}, ComponentID: "x9000c1s0b0"},
+			{ID: "aabbccddee01", Description: "eth0", MACAddress: "aa:bb:cc:dd:ee:01", IPAddresses: []IPAddress{{IPAddress: "10.42.0.7", Network: "nmn"}}, ComponentID: "x9000c1s0b0n0"},
+			{ID: "0a0000000099", MACAddress: "0a:00:00:00:00:99", ComponentID: "x9000c9s0b0"},
+		},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	c := NewClient(server.URL, "", false, 5*time.Second)
+	ctx := context.Background()
+
+	remote, err := c.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := Diff(local, remote)
+	var got []string
+	for _, ch := range changes {
+		got = append(got, fmt.Sprintf("%s %s %s %v", ch.Action, ch.Kind, ch.ID, ch.Fields))
+	}
+	want := []string{
+		"update RedfishEndpoint x9000c1s0b0 [FQDN IPAddress]",
+		"delete RedfishEndpoint x9000c9s0b0 []",
+		"create EthernetInterface 020000000001 []",
+		"update EthernetInterface aabbccddee01 [IPAddresses]",
+		"delete EthernetInterface 0a0000000099 []",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if err := c.Apply(ctx, changes); err != nil {
+		t.Fatal(err)
+	}
+	wantReqs := []string{
+		"PATCH " + RedfishEndpointsPath + "/x9000c1s0b0",
+		"DELETE " + RedfishEndpointsPath + "/x9000c9s0b0",
+		"POST " + EthernetInterfacesPath,
+		"PATCH " + EthernetInterfacesPath + "/aabbccddee01",
+		"DELETE " + EthernetInterfacesPath + "/0a0000000099",
+	}
+	if !reflect.DeepEqual(fake.requests, wantReqs) {
+		t.Fatalf("requests = %v", fake.requests)
+	}
+	if remote, err = c.Get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if changes := Diff(local, remote); len(changes) != 0 {
+		t.Fatalf("SMD still differs after apply: %+v", changes)
+	}
+}
+
+func TestToInventory(t *testing.T) {
+	p := FromInventory(testInventory(), Credentials{})
+	doc := ToInventory(p)
+	if len(doc.BMCs) != 1 || !reflect.DeepEqual(doc.BMCs[0], inventory.Entry{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"}) {
+		t.Errorf("BMCs = %+v", doc.BMCs)
+	}
+	n := doc.Nodes[0]
+	if n.Xname != "x9000c1s0b0n0" || n.NID != 1 || n.Role != "Compute" || n.BMC != "x9000c1s0b0" || n.MAC != "aa:bb:cc:dd:ee:01" || n.IP != "10.42.0.1" {
+		t.Errorf("node = %+v", n)
+	}
+	wantIfaces := []inventory.Interface{
+		{Name: "hsn0", MAC: "02:00:00:00:00:01", IP: "10.150.0.1", Network: "hsn"},
+		{Name: "eth0", MAC: "aa:bb:cc:dd:ee:01", IP: "10.42.0.1", Network: "nmn"},
+	}
+	if !reflect.DeepEqual(n.Interfaces, wantIfaces) {
+		t.Errorf("interfaces = %+v", n.Interfaces)
+	}
+	// What was pulled exports to the same SMD objects
+	if changes := Diff(FromInventory(doc, Credentials{}), p); len(changes) != 0 {
+		t.Errorf("pulled inventory differs: %+v", changes)
+	}
+
+	// A host:port endpoint comes back with its port
+	doc = testInventory()
+	doc.BMCs[0].IP = "127.0.0.1:8443"
+	if got := ToInventory(FromInventory(doc, Credentials{})).BMCs[0].IP; got != "127.0.0.1:8443" {
+		t.Errorf("endpoint BMC IP = %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package updateplan defines the multi-step firmware update plan file: an ordered set of
+// baseline components, each of which may depend on others having been applied first.
+package updateplan
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"bootstrap/internal/baseline"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step is one update in the plan. It has the same fields as a baseline component plus the
+// names of the steps that must be verified on a host before this one starts there, and how
+// node power is handled for updates that only take effect on reboot, such as BIOS.
+type Step struct {
+	baseline.Component `yaml:",inline"`
+	DependsOn          []string `yaml:"depends_on,omitempty"`
+	// Reboot is never (default), after (restart the nodes that are on once the update task
+	// finishes) or required (power nodes off before the update and back on after).
+	Reboot string `yaml:"reboot,omitempty"`
+}
+
+// File is the root of a plan YAML document.
+type File struct {
+	Steps []Step `yaml:"steps"`
+}
+
+// Load reads and validates a plan file.
+func Load(file string) (*File, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc File
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse plan %s: %w", file, err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("plan %s: %w", file, err)
+	}
+	return &doc, nil
+}
+
+// Validate checks each step like a baseline component, and that step names are unique and
+// their dependencies exist and do not form a cycle.
+func (f *File) Validate() error {
+	if len(f.Steps) == 0 {
+		return errors.New("steps[] must not be empty")
+	}
+	components := make([]baseline.Component, len(f.Steps))
+	for i, s := range f.Steps {
+		components[i] = s.Component
+	}
+	var errs []error
+	if err := (&baseline.File{Components: components}).Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	names := map[string]bool{}
+	for i, s := range f.Steps {
+		switch {
+		case s.Name == "":
+			errs = append(errs, fmt.Errorf("steps[%d]: name is required", i))
+		case names[s.Name]:
+			errs = append(errs, fmt.Errorf("%s: duplicate step name", s.Name))
+		}
+		switch s.Reboot {
+		case "", "never", "after", "required":
+		default:
+			errs = append(errs, fmt.Errorf("%s: invalid reboot %q (want never, after or required)", s.Name, s.Reboot))
+		}
+		names[s.Name] = true
+	}
+	for _, s := range f.Steps {
+		for _, d := range s.DependsOn {
+			if !names[d] {
+				errs = append(errs, fmt.Errorf("%s: depends_on unknown step %q", s.Name, d))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	_, err := f.Order()
+	return err
+}
+
+// Order returns the steps sorted so that every step comes after the steps it depends on.
+// Steps whose dependencies are met keep their file order.
+func (f *File) Order() ([]Step, error) {
+	done := map[string]bool{}
+	out := make([]Step, 0, len(f.Steps))
+	for len(out) < len(f.Steps) {
+		progress := false
+		for _, s := range f.Steps {
+			if done[s.Name] || !f.ready(s, done) {
+				continue
+			}
+			done[s.Name] = true
+			out = append(out, s)
+			progress = true
+			// Restart from the top so earlier steps that just became ready go first
+			break
+		}
+		if !progress {
+			var stuck []string
+			for _, s := range f.Steps {
+				if !done[s.Name] {
+					stuck = append(stuck, s.Name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between steps %v", stuck)
+		}
+	}
+	return out, nil
+}
+
+func (f *File) ready(s Step, done map[string]bool) bool {
+	for _, d := range s.DependsOn {
+		if !done[d] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package updateplan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePlan(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "plan.yaml")
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoadAndOrder(t *testing.T) {
+	p := writePlan(t, `steps:
+  - name: nic
+    targets: [/redfish/v1/UpdateService/FirmwareInventory/NIC0]
+    version: "22.1"
+    image_uri: http://10.0.0.1/nic.bin
+    depends_on: [bmc, bios]
+  - name: bios
+    type: bios
+    version: "1.6"
+    image_file: bios-1.6.cap
+    depends_on: [bmc]
+    reboot: required
+  - name: bmc
+    type: bmc
+    version: nc.1.9.8
+    image_uri: http://10.0.0.1/bmc.bin
+`)
+	doc, err := Load(p)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	steps, err := doc.Order()
+	if err != nil {
+		t.Fatalf("Order failed: %v", err)
+	}
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "bmc,bios,nic" {
+		t.Fatalf("order = %s, want bmc,bios,nic", got)
+	}
+	if steps[1].ImageFile != "bios-1.6.cap" || steps[1].Reboot != "required" {
+		t.Errorf("inline component fields not loaded: %+v", steps[1])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"unknown dependency", `steps:
+  - {name: bmc, type: bmc, version: "1", image_uri: http://h/b.bin, depends_on: [nic]}
+`, `depends_on unknown step "nic"`},
+		{"duplicate", `steps:
+  - {name: bmc, type: bmc, version: "1", image_uri: http://h/b.bin}
+  - {name: bmc, type: bmc, version: "2", image_uri: http://h/c.bin}
+`, "duplicate step name"},
+		{"cycle", `steps:
+  - {name: a, type: bmc, version: "1", image_uri: http://h/a.bin, depends_on: [b]}
+  - {name: b, type: bios, version: "1", image_uri: http://h/b.bin, depends_on: [a]}
+`, "dependency cycle between steps [a b]"},
+		{"component fields", `steps:
+  - {name: bmc, type: bmc}
+`, "version is required"},
+		{"reboot", `steps:
+  - {name: bios, type: bios, version: "1", image_uri: http://h/b.bin, reboot: always}
+`, `bios: invalid reboot "always"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writePlan(t, tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package version parses vendor firmware version strings into comparable versions.
+// Parsers are pluggable: exact strings ordered by their first dotted numeric run, strict
+// semantic versions, the first dotted numeric run alone (so "nc.1.5.2" and "1.5.2-build37"
+// compare equal), or a user-supplied regular expression whose capture groups are the
+// numeric components.
+package version
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed firmware version. Parts are compared numerically left to right,
+// with missing trailing parts treated as zero. Pre is a semver pre-release, which sorts
+// before the same version without one.
+type Version struct {
+	Raw   string
+	Parts []int
+	Pre   string
+}
+
+func (v Version) String() string { return v.Raw }
+
+// Parser turns a version string into a Version.
+type Parser interface {
+	Parse(s string) (Version, error)
+}
+
+// Matcher is implemented by parsers that decide whether two version strings are the same
+// version themselves instead of by Compare.
+type Matcher interface {
+	Match(a, b string) bool
+}
+
+// ParserFunc adapts a function to the Parser interface.
+type ParserFunc func(s string) (Version, error)
+
+// Parse calls f(s).
+func (f ParserFunc) Parse(s string) (Version, error) { return f(s) }
+
+var (
+	semverRe = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+	dottedRe = regexp.MustCompile(`\d+(?:\.\d+)*`)
+)
+
+// Semver parses MAJOR.MINOR.PATCH with an optional leading "v", pre-release and build
+// metadata. Build metadata is ignored for comparison.
+var Semver Parser = ParserFunc(func(s string) (Version, error) {
+	m := semverRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("%q is not a semantic version", s)
+	}
+	parts, err := atoiAll(m[1:4])
+	if err != nil {
+		return Version{}, err
+	}
+	return Version{Raw: s, Parts: parts, Pre: m[4]}, nil
+})
+
+// Dotted parses the first run of dot-separated numbers anywhere in the string, ignoring
+// vendor prefixes and suffixes.
+var Dotted Parser = ParserFunc(func(s string) (Version, error) {
+	run := dottedRe.FindString(s)
+	if run == "" {
+		return Version{}, fmt.Errorf("%q contains no numeric version", s)
+	}
+	parts, err := atoiAll(strings.Split(run, "."))
+	if err != nil {
+		return Version{}, err
+	}
+	return Version{Raw: s, Parts: parts}, nil
+})
+
+type exact struct{}
+
+func (exact) Parse(s string) (Version, error) { return Dotted.Parse(s) }
+func (exact) Match(a, b string) bool          { return a == b }
+
+// Exact orders versions like Dotted but only treats identical strings as the same version,
+// so "1.5.2-build37" and "1.5.2-build38" are neither equal nor one newer than the other.
+var Exact Parser = exact{}
+
+// Regex returns a parser that matches pattern against the version string and uses its
+// capture groups, in order, as numeric components. Groups that did not participate in the
+// match count as zero.
+func Regex(pattern string) (Parser, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("version regex: %w", err)
+	}
+	if re.NumSubexp() == 0 {
+		return nil, errors.New("version regex must have at least one capture group")
+	}
+	return ParserFunc(func(s string) (Version, error) {
+		m := re.FindStringSubmatch(s)
+		if m == nil {
+			return Version{}, fmt.Errorf("%q does not match %s", s, pattern)
+		}
+		parts := make([]int, 0, len(m)-1)
+		for _, g := range m[1:] {
+			if g == "" {
+				parts = append(parts, 0)
+				continue
+			}
+			n, err := strconv.Atoi(g)
+			if err != nil {
+				return Version{}, fmt.Errorf("%q: capture group %q is not numeric", s, g)
+			}
+			parts = append(parts, n)
+		}
+		return Version{Raw: s, Parts: parts}, nil
+	}), nil
+}
+
+// ParseScheme returns the parser for a --version-scheme value: "exact" (the default when
+// empty), "dotted", "semver", or "regex:<pattern>".
+func ParseScheme(scheme string) (Parser, error) {
+	switch {
+	case scheme == "" || strings.EqualFold(scheme, "exact"):
+		return Exact, nil
+	case strings.EqualFold(scheme, "dotted"):
+		return Dotted, nil
+	case strings.EqualFold(scheme, "semver"):
+		return Semver, nil
+	case strings.HasPrefix(scheme, "regex:"):
+		return Regex(strings.TrimPrefix(scheme, "regex:"))
+	default:
+		return nil, fmt.Errorf("unknown version scheme %q (want exact, dotted, semver or regex:<pattern>)", scheme)
+	}
+}
+
+func atoiAll(ss []string) ([]int, error) {
+	out := make([]int, len(ss))
+	for i, s := range ss {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// Compare returns -1, 0 or 1 as a is less than, equal to or greater than b.
+func Compare(a, b Version) int {
+	for i := 0; i < max(len(a.Parts), len(b.Parts)); i++ {
+		var x, y int
+		if i < len(a.Parts) {
+			x = a.Parts[i]
+		}
+		if i < len(b.Parts) {
+			y = b.Parts[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePre(a.Pre, b.Pre)
+}
+
+// comparePre orders pre-releases per semver: no pre-release sorts last, numeric
+// identifiers compare numerically and sort before alphanumeric ones.
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		x, errX := strconv.Atoi(as[i])
+		y, errY := strconv.Atoi(bs[i])
+		switch {
+		case errX == nil && errY == nil:
+			if x < y {
+				return -1
+			}
+			return 1
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		case as[i] < bs[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// Policy bounds acceptable versions. A nil bound is open.
+type Policy struct {
+	Parser Parser
+	Min    *Version
+	Max    *Version
+}
+
+// NewPolicy parses the min and max bounds (either may be empty) with p.
+func NewPolicy(p Parser, minVersion, maxVersion string) (Policy, error) {
+	pol := Policy{Parser: p}
+	if minVersion != "" {
+		v, err := p.Parse(minVersion)
+		if err != nil {
+			return pol, fmt.Errorf("min version: %w", err)
+		}
+		pol.Min = &v
+	}
+	if maxVersion != "" {
+		v, err := p.Parse(maxVersion)
+		if err != nil {
+			return pol, fmt.Errorf("max version: %w", err)
+		}
+		pol.Max = &v
+	}
+	if pol.Min != nil && pol.Max != nil && Compare(*pol.Min, *pol.Max) > 0 {
+		return pol, fmt.Errorf("min version %s is greater than max version %s", minVersion, maxVersion)
+	}
+	return pol, nil
+}
+
+// Active reports whether the policy has any bound.
+func (p Policy) Active() bool { return p.Min != nil || p.Max != nil }
+
+// Check returns an empty string when s is within the policy, otherwise "below-min",
+// "above-max" or "unparsable".
+func (p Policy) Check(s string) string {
+	if !p.Active() {
+		return ""
+	}
+	v, err := p.Parser.Parse(s)
+	if err != nil {
+		return "unparsable"
+	}
+	if p.Min != nil && Compare(v, *p.Min) < 0 {
+		return "below-min"
+	}
+	if p.Max != nil && Compare(v, *p.Max) > 0 {
+		return "above-max"
+	}
+	return ""
+}
+
+// CompareStrings parses a and b with p and compares them. ok is false when either
+// does not parse.
+func CompareStrings(p Parser, a, b string) (c int, ok bool) {
+	va, err := p.Parse(a)
+	if err != nil {
+		return 0, false
+	}
+	vb, err := p.Parse(b)
+	if err != nil {
+		return 0, false
+	}
+	return Compare(va, vb), true
+}
+
+// Equal reports whether a and b are the same version under p, falling back to exact
+// string comparison when either does not parse.
+func Equal(p Parser, a, b string) bool {
+	if m, ok := p.(Matcher); ok {
+		return m.Match(a, b)
+	}
+	if c, ok := CompareStrings(p, a, b); ok {
+		return c == 0
+	}
+	return a == b
+}
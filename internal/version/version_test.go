@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package version
+
+import "testing"
+
+func TestCompareStrings(t *testing.T) {
+	vendor, err := Regex(`^(\d+)\.(\d+)\.(\d+)-build(\d+)$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		parser Parser
+		a, b   string
+		want   int
+	}{
+		{"dotted minor", Dotted, "2.10", "2.9", 1},
+		{"dotted vendor prefix and suffix", Dotted, "1.5.2-build37", "nc.1.5.2", 0},
+		{"dotted missing part", Dotted, "1.5", "1.5.0", 0},
+		{"dotted older", Dotted, "nc.1.9.0", "nc.1.11.0", -1},
+		{"semver prerelease", Semver, "1.2.0-rc.1", "1.2.0", -1},
+		{"semver numeric prerelease", Semver, "1.2.0-rc.2", "1.2.0-rc.10", -1},
+		{"semver build metadata", Semver, "v1.2.3+abc", "1.2.3", 0},
+		{"regex build number", vendor, "1.5.2-build37", "1.5.2-build4", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CompareStrings(tt.parser, tt.a, tt.b)
+			if !ok || got != tt.want {
+				t.Errorf("CompareStrings(%q, %q) = %d, %v; want %d", tt.a, tt.b, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Semver.Parse("nc.1.5.2"); err == nil {
+		t.Error("expected semver error for vendor prefix")
+	}
+	if _, err := Dotted.Parse("unknown"); err == nil {
+		t.Error("expected dotted error without digits")
+	}
+	if _, err := Regex(`\d+`); err == nil {
+		t.Error("expected error for regex without capture groups")
+	}
+	if _, err := ParseScheme("calver"); err == nil {
+		t.Error("expected unknown scheme error")
+	}
+	if Equal(Dotted, "(unknown)", "1.0") {
+		t.Error("unparsable version should not equal 1.0")
+	}
+}
+
+func TestExact(t *testing.T) {
+	if p, err := ParseScheme(""); err != nil || p != Exact {
+		t.Fatalf("default scheme = %v, %v; want Exact", p, err)
+	}
+	if Equal(Exact, "1.5.2-build37", "1.5.2-build38") {
+		t.Error("build-only difference should not be equal")
+	}
+	if !Equal(Exact, "nc.1.5.2", "nc.1.5.2") {
+		t.Error("identical strings should be equal")
+	}
+	if c, ok := CompareStrings(Exact, "nc.1.5.2", "1.5.10"); !ok || c != -1 {
+		t.Errorf("CompareStrings = %d, %v; want -1", c, ok)
+	}
+	if c, ok := CompareStrings(Exact, "1.5.2-build38", "1.5.2-build37"); !ok || c != 0 {
+		t.Errorf("build-only difference should not order, got %d, %v", c, ok)
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	p, err := NewPolicy(Dotted, "1.5", "2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v, want := range map[string]string{
+		"nc.1.4.9":  "below-min",
+		"nc.1.5.0":  "",
+		"2.0":       "",
+		"2.0.1":     "above-max",
+		"(unknown)": "unparsable",
+	} {
+		if got := p.Check(v); got != want {
+			t.Errorf("Check(%q) = %q, want %q", v, got, want)
+		}
+	}
+	if _, err := NewPolicy(Dotted, "3", "2"); err == nil {
+		t.Error("expected error when min > max")
+	}
+}
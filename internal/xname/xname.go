@@ -27,3 +27,31 @@ func BMCXnameToNodeN(bmcX string, nodeNum int) string {
 	// Append nY where Y is the nodeNum
 	return fmt.Sprintf("%sn%d", bmcX, nodeNum)
 }
+
+var trailingN = regexp.MustCompile(`(b\d+)n\d+$`)
+
+// NodeToBMC returns the xname of the BMC that manages a node, e.g. x9000c1s0b0n1 ->
+// x9000c1s0b0. It returns an empty string when the xname is not a node under a BMC.
+func NodeToBMC(nodeX string) string {
+	if !trailingN.MatchString(nodeX) {
+		return ""
+	}
+	return trailingN.ReplaceAllString(nodeX, "$1")
+}
+
+var (
+	cabinetPrefix = regexp.MustCompile(`^x\d+`)
+	chassisPrefix = regexp.MustCompile(`^x\d+c\d+`)
+)
+
+// Cabinet returns the cabinet portion of an xname, e.g. x9000c1s0b0 -> x9000.
+// It returns an empty string when the xname does not start with a cabinet.
+func Cabinet(x string) string {
+	return cabinetPrefix.FindString(x)
+}
+
+// Chassis returns the chassis portion of an xname, e.g. x9000c1s0b0 -> x9000c1.
+// It returns an empty string when the xname does not include a chassis.
+func Chassis(x string) string {
+	return chassisPrefix.FindString(x)
+}
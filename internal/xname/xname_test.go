@@ -44,3 +44,40 @@ func TestBMCXnameToNodeN(t *testing.T) {
 		}
 	}
 }
+
+func TestCabinetAndChassis(t *testing.T) {
+	cases := []struct {
+		in      string
+		cabinet string
+		chassis string
+	}{
+		{"x9000c1s0b0", "x9000", "x9000c1"},
+		{"x1000c3s7b1n0", "x1000", "x1000c3"},
+		{"x3000", "x3000", ""},
+		{"10.1.1.10", "", ""},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		if got := Cabinet(c.in); got != c.cabinet {
+			t.Fatalf("Cabinet(%q)=%q want %q", c.in, got, c.cabinet)
+		}
+		if got := Chassis(c.in); got != c.chassis {
+			t.Fatalf("Chassis(%q)=%q want %q", c.in, got, c.chassis)
+		}
+	}
+}
+
+func TestNodeToBMC(t *testing.T) {
+	cases := map[string]string{
+		"x9000c1s0b0n1": "x9000c1s0b0",
+		"x1000c3s7b1n0": "x1000c3s7b1",
+		"x9000c1s0b0":   "",
+		"x9000c1s0n0":   "",
+		"10.1.1.10":     "",
+	}
+	for in, want := range cases {
+		if got := NodeToBMC(in); got != want {
+			t.Fatalf("NodeToBMC(%q)=%q want %q", in, got, want)
+		}
+	}
+}